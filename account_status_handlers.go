@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"net/http"
+)
+
+// statusActions maps each account status to the moderation action that
+// records an admin moving a user into it.
+var statusActions = map[string]string{
+	userStatusActive:       moderationReinstate,
+	userStatusSuspended:    moderationSuspend,
+	userStatusShadowbanned: moderationShadowban,
+}
+
+func (cfg *apiConfig) handleSetUserStatus(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authorizeAdmin(w, r) {
+		return
+	}
+
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid User ID")
+		return
+	}
+
+	var reqBody struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	action, ok := statusActions[reqBody.Status]
+	if !ok {
+		respondWithError(w, http.StatusBadRequest, `Status must be "active", "suspended" or "shadowbanned"`)
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	dbUser, err := qtx.GetUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+			return
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if dbUser.Status != reqBody.Status {
+		err = changeUserStatus(r.Context(), qtx, userID, reqBody.Status, action, reqBody.Note)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+
+		err = recordAudit(r, qtx, auditEntry{
+			actorType:  auditActorAdmin,
+			action:     action,
+			targetType: auditTargetUser,
+			targetID:   uuid.NullUUID{UUID: userID, Valid: true},
+			diff: map[string]auditChange{
+				"status": {Old: dbUser.Status, New: reqBody.Status},
+			},
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+
+		dbUser, err = qtx.GetUserByID(r.Context(), userID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	status := AccountStatus{
+		UserID: dbUser.ID,
+		Status: dbUser.Status,
+	}
+	if dbUser.StatusChangedAt.Valid {
+		status.StatusChangedAt = &dbUser.StatusChangedAt.Time
+	}
+
+	respondWithJSON(w, http.StatusOK, status)
+}
+
+// changeUserStatus moves a user into a new status and records the decision in
+// the moderation log. Account-level decisions have no chirp or reports.
+func changeUserStatus(ctx context.Context, q *database.Queries, userID uuid.UUID, status, action, note string) error {
+	_, err := q.CreateModerationAction(ctx, database.CreateModerationActionParams{
+		UserID: uuid.NullUUID{UUID: userID, Valid: true},
+		Action: action,
+		Note:   note,
+	})
+	if err != nil {
+		return err
+	}
+
+	return setUserStatus(ctx, q, userID, status)
+}
+
+// authorHiddenFrom reports whether the viewer cannot see any of the author's
+// chirps: either one blocked the other, or the author is shadowbanned or
+// suspended. Shadowbanned users still see their own chirps.
+func (cfg *apiConfig) authorHiddenFrom(ctx context.Context, viewerID uuid.NullUUID, authorID uuid.UUID) (bool, error) {
+	if viewerID.Valid && viewerID.UUID == authorID {
+		return false, nil
+	}
+
+	status, err := cfg.dbQueries.GetUserStatus(ctx, authorID)
+	if err != nil {
+		return false, err
+	}
+
+	if status == userStatusShadowbanned || status == userStatusSuspended {
+		return true, nil
+	}
+
+	return cfg.blockedBetween(ctx, viewerID, authorID)
+}
+
+// addShadowbannedOrSuspendedAuthors adds the shadowbanned and suspended
+// authors of dbChirps to hidden, leaving out the viewer.
+func (cfg *apiConfig) addShadowbannedOrSuspendedAuthors(ctx context.Context, viewerID uuid.NullUUID, dbChirps []database.Chirp, hidden map[uuid.UUID]bool) error {
+	authorIDs := make([]uuid.UUID, 0, len(dbChirps))
+	for _, dbChirp := range dbChirps {
+		if viewerID.Valid && dbChirp.UserID == viewerID.UUID {
+			continue
+		}
+		authorIDs = append(authorIDs, dbChirp.UserID)
+	}
+
+	if len(authorIDs) == 0 {
+		return nil
+	}
+
+	restrictedIDs, err := cfg.dbQueries.ListShadowbannedOrSuspendedUsers(ctx, authorIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range restrictedIDs {
+		hidden[userID] = true
+	}
+
+	return nil
+}
@@ -1,15 +1,23 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/chirptext"
+	"github.com/pedroomedicina/chirpy/internal/contentfilter"
 	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/entitlements"
+	"github.com/pedroomedicina/chirpy/internal/media"
+	"github.com/pedroomedicina/chirpy/internal/ratelimit"
+	"github.com/pedroomedicina/chirpy/internal/spam"
 	"net/http"
 	"sync/atomic"
+	"time"
 )
 
 type apiConfig struct {
@@ -18,7 +26,25 @@ type apiConfig struct {
 	platform       string
 	jwtSecret      string
 	polkaKey       string
+	adminKey       string
 	db             *sql.DB
+	trendingWindow time.Duration
+	mediaStorage   media.Storage
+	// Uploads not attached to a chirp within unattachedMediaTTL are deleted.
+	unattachedMediaTTL time.Duration
+	trashRetention     time.Duration
+	// Users can change their handle once per handleChangeInterval. Their old
+	// handle redirects to the new one for handleRedirectPeriod.
+	handleChangeInterval time.Duration
+	handleRedirectPeriod time.Duration
+	plans                entitlements.Plans
+	writeLimiter         *ratelimit.Limiter
+	bannedWords          *contentfilter.WordFilter
+	contentFilters       contentfilter.Pipeline
+	// reportHideThreshold is how many distinct users have to report a chirp
+	// before it is hidden pending review.
+	reportHideThreshold int
+	spamConfig          spam.Config
 }
 
 func (cfg *apiConfig) middlewareMetricsInc(next http.Handler) http.Handler {
@@ -28,14 +54,92 @@ func (cfg *apiConfig) middlewareMetricsInc(next http.Handler) http.Handler {
 	})
 }
 
-func (cfg *apiConfig) validateChirp(chirp Chirp) error {
-	if len(chirp.Body) > 140 {
+const (
+	userStatusActive       = "active"
+	userStatusSuspended    = "suspended"
+	userStatusShadowbanned = "shadowbanned"
+)
+
+var (
+	errInvalidToken     = errors.New("Invalid or expired token")
+	errAccountSuspended = errors.New("Account suspended")
+)
+
+// authenticate validates an access token. Access tokens outlive a suspension
+// by up to an hour, so the user's status is checked on every request.
+func (cfg *apiConfig) authenticate(ctx context.Context, tokenString string) (uuid.UUID, error) {
+	userID, err := auth.ValidateJWT(tokenString, cfg.jwtSecret)
+	if err != nil {
+		return uuid.Nil, errInvalidToken
+	}
+
+	status, err := cfg.dbQueries.GetUserStatus(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, errInvalidToken
+		}
+		return uuid.Nil, err
+	}
+
+	if status == userStatusSuspended {
+		return uuid.Nil, errAccountSuspended
+	}
+
+	return userID, nil
+}
+
+func respondWithAuthError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errInvalidToken):
+		respondWithError(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, errAccountSuspended):
+		respondWithError(w, http.StatusForbidden, err.Error())
+	default:
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+}
+
+// viewerID identifies the caller of an endpoint that does not require
+// authentication. A missing or invalid bearer token yields an anonymous viewer,
+// and so does a suspended user's token.
+func (cfg *apiConfig) viewerID(r *http.Request) uuid.NullUUID {
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		return uuid.NullUUID{}
+	}
+
+	userID, err := cfg.authenticate(r.Context(), tokenString)
+	if err != nil {
+		return uuid.NullUUID{}
+	}
+
+	return uuid.NullUUID{UUID: userID, Valid: true}
+}
+
+func (cfg *apiConfig) validateChirp(chirp Chirp, limits entitlements.Limits) error {
+	if remainingCharacters(chirp.Body, limits) < 0 {
 		return errors.New("Chirp too long")
 	}
 
+	if len(chirp.Media) > limits.MaxMediaPerChirp {
+		return fmt.Errorf("A chirp can have at most %d media attachments", limits.MaxMediaPerChirp)
+	}
+
+	for _, attachment := range chirp.Media {
+		if len(attachment.AltText) > maxAltTextLength {
+			return errors.New("Alt text too long")
+		}
+	}
+
 	return nil
 }
 
+// remainingCharacters is how much of the caller's length limit is left after
+// body. It is negative when body is too long.
+func remainingCharacters(body string, limits entitlements.Limits) int {
+	return limits.MaxChirpLength - chirptext.Length(body)
+}
+
 func (cfg *apiConfig) handleMetrics(w http.ResponseWriter, _ *http.Request) {
 	hits := cfg.fileserverHits.Load()
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -60,7 +164,35 @@ func (cfg *apiConfig) handleReset(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := cfg.dbQueries.DeleteAllUsers(r.Context())
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	deletedUsers, err := qtx.DeleteAllUsers(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	err = recordAudit(r, qtx, auditEntry{
+		actorType:  auditActorAdmin,
+		action:     auditActionReset,
+		targetType: auditTargetDatabase,
+		diff: map[string]auditChange{
+			"users":           {Old: deletedUsers, New: 0},
+			"fileserver_hits": {Old: cfg.fileserverHits.Load(), New: 0},
+		},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
@@ -106,7 +238,15 @@ func (cfg *apiConfig) handlePolkaWebHook(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = cfg.dbQueries.UpgradeUserToChirpyRed(r.Context(), userID)
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to process webhook")
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	dbUser, err := qtx.GetUserByID(r.Context(), userID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			respondWithError(w, http.StatusNotFound, "User not found")
@@ -116,5 +256,37 @@ func (cfg *apiConfig) handlePolkaWebHook(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Polka retries webhooks, so only the first delivery changes the plan.
+	if dbUser.IsChirpyRed {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	err = qtx.UpgradeUserToChirpyRed(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to process webhook")
+		return
+	}
+
+	err = recordAudit(r, qtx, auditEntry{
+		actorType:  auditActorWebhook,
+		action:     auditActionPlanChange,
+		targetType: auditTargetUser,
+		targetID:   uuid.NullUUID{UUID: userID, Valid: true},
+		diff: map[string]auditChange{
+			"plan": {Old: entitlements.PlanFree, New: entitlements.PlanChirpyRed},
+		},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to process webhook")
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to process webhook")
+		return
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
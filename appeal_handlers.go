@@ -0,0 +1,286 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/pagination"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	appealStatusPending  = "pending"
+	appealStatusApproved = "approved"
+	appealStatusDenied   = "denied"
+
+	maxAppealMessageLength = 2000
+)
+
+// handleCreateAppeal lets a suspended user contest their suspension. Suspended
+// users cannot get an access token, so the appeal is signed with their email
+// and password instead. Each suspension can be appealed once.
+func (cfg *apiConfig) handleCreateAppeal(w http.ResponseWriter, r *http.Request) {
+	var reqBody struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+		Message  string `json:"message"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil || reqBody.Email == "" || reqBody.Password == "" {
+		respondWithError(w, http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return
+	}
+
+	message := strings.TrimSpace(reqBody.Message)
+	if message == "" {
+		respondWithError(w, http.StatusBadRequest, "Appeal message is required")
+		return
+	}
+
+	if utf8.RuneCountInString(message) > maxAppealMessageLength {
+		respondWithError(w, http.StatusBadRequest, "Appeal message too long")
+		return
+	}
+
+	dbUser, err := cfg.dbQueries.GetUserByEmail(r.Context(), reqBody.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		} else {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		}
+		return
+	}
+
+	err = auth.CheckPasswordHash(reqBody.Password, dbUser.HashedPassword)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	if dbUser.Status != userStatusSuspended {
+		respondWithError(w, http.StatusBadRequest, "Account is not suspended")
+		return
+	}
+
+	dbAppeal, err := cfg.dbQueries.CreateAppeal(r.Context(), database.CreateAppealParams{
+		UserID:      dbUser.ID,
+		SuspendedAt: dbUser.StatusChangedAt.Time,
+		Message:     message,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusConflict, "Appeal already filed")
+			return
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, databaseAppealToAppeal(dbAppeal))
+}
+
+// handleGetAppeals lists appeals oldest first, pending ones unless another
+// status is asked for.
+func (cfg *apiConfig) handleGetAppeals(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authorizeAdmin(w, r) {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = appealStatusPending
+	}
+	if status != appealStatusPending && status != appealStatusApproved && status != appealStatusDenied {
+		respondWithError(w, http.StatusBadRequest, `Status must be "pending", "approved" or "denied"`)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := database.ListAppealsParams{
+		Status: status,
+		Limit:  limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	dbAppeals, err := cfg.dbQueries.ListAppeals(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	page := AppealsPage{Appeals: []Appeal{}}
+	if len(dbAppeals) > int(limit) {
+		dbAppeals = dbAppeals[:limit]
+		last := dbAppeals[len(dbAppeals)-1]
+		page.NextCursor = pagination.EncodeCursor(pagination.Cursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+	}
+
+	for _, dbAppeal := range dbAppeals {
+		page.Appeals = append(page.Appeals, databaseAppealToAppeal(dbAppeal))
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+func (cfg *apiConfig) handleApproveAppeal(w http.ResponseWriter, r *http.Request) {
+	cfg.decideAppeal(w, r, appealStatusApproved)
+}
+
+func (cfg *apiConfig) handleDenyAppeal(w http.ResponseWriter, r *http.Request) {
+	cfg.decideAppeal(w, r, appealStatusDenied)
+}
+
+// decideAppeal closes a pending appeal. Approving it reinstates the user if
+// they are still serving the suspension the appeal was filed against.
+func (cfg *apiConfig) decideAppeal(w http.ResponseWriter, r *http.Request, decision string) {
+	if !cfg.authorizeAdmin(w, r) {
+		return
+	}
+
+	appealID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid Appeal ID")
+		return
+	}
+
+	var reqBody struct {
+		Note string `json:"note"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil && !errors.Is(err, io.EOF) {
+		respondWithError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	dbAppeal, err := qtx.DecideAppeal(r.Context(), database.DecideAppealParams{
+		ID:           appealID,
+		Status:       decision,
+		DecisionNote: reqBody.Note,
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+
+		_, err = qtx.GetAppealByID(r.Context(), appealID)
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		} else if err != nil {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		} else {
+			respondWithError(w, http.StatusConflict, "Appeal already decided")
+		}
+		return
+	}
+
+	auditAction := auditActionAppealDeny
+	if decision == appealStatusApproved {
+		auditAction = auditActionAppealApprove
+	}
+
+	err = recordAudit(r, qtx, auditEntry{
+		actorType:  auditActorAdmin,
+		action:     auditAction,
+		targetType: auditTargetAppeal,
+		targetID:   uuid.NullUUID{UUID: dbAppeal.ID, Valid: true},
+		diff: map[string]auditChange{
+			"status": {Old: appealStatusPending, New: decision},
+		},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if decision == appealStatusApproved {
+		dbUser, err := qtx.GetUserByID(r.Context(), dbAppeal.UserID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+
+		if dbUser.Status == userStatusSuspended && dbUser.StatusChangedAt.Time.Equal(dbAppeal.SuspendedAt) {
+			err = changeUserStatus(r.Context(), qtx, dbUser.ID, userStatusActive, moderationReinstate, reqBody.Note)
+			if err != nil {
+				respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+				return
+			}
+
+			err = recordAudit(r, qtx, auditEntry{
+				actorType:  auditActorAdmin,
+				action:     moderationReinstate,
+				targetType: auditTargetUser,
+				targetID:   uuid.NullUUID{UUID: dbUser.ID, Valid: true},
+				diff: map[string]auditChange{
+					"status": {Old: userStatusSuspended, New: userStatusActive},
+				},
+			})
+			if err != nil {
+				respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+				return
+			}
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseAppealToAppeal(dbAppeal))
+}
+
+func databaseAppealToAppeal(dbAppeal database.Appeal) Appeal {
+	appeal := Appeal{
+		ID:           dbAppeal.ID,
+		CreatedAt:    dbAppeal.CreatedAt,
+		UserID:       dbAppeal.UserID,
+		SuspendedAt:  dbAppeal.SuspendedAt,
+		Message:      dbAppeal.Message,
+		Status:       dbAppeal.Status,
+		DecisionNote: dbAppeal.DecisionNote,
+	}
+
+	if dbAppeal.DecidedAt.Valid {
+		appeal.DecidedAt = &dbAppeal.DecidedAt.Time
+	}
+
+	return appeal
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/pagination"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	auditActorAdmin   = "admin"
+	auditActorUser    = "user"
+	auditActorWebhook = "webhook"
+
+	// Moderation decisions are logged under the moderation action's name.
+	auditActionReset            = "reset"
+	auditActionPlanChange       = "plan_change"
+	auditActionEmailChange      = "email_change"
+	auditActionPasswordChange   = "password_change"
+	auditActionHandleChange     = "handle_change"
+	auditActionBannedWordCreate = "banned_word_create"
+	auditActionBannedWordUpdate = "banned_word_update"
+	auditActionBannedWordDelete = "banned_word_delete"
+	auditActionAppealApprove    = "appeal_approve"
+	auditActionAppealDeny       = "appeal_deny"
+
+	auditTargetAppeal     = "appeal"
+	auditTargetBannedWord = "banned_word"
+	auditTargetChirp      = "chirp"
+	auditTargetDatabase   = "database"
+	auditTargetUser       = "user"
+
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+
+	// auditExportBatchSize is how many entries a CSV export reads at a time.
+	auditExportBatchSize = 500
+)
+
+type requestIDKey struct{}
+
+// middlewareRequestID tags every request with an ID, reusing the caller's
+// X-Request-ID when it has one, and echoes it back in the response.
+func middlewareRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		w.Header().Set(requestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, requestID)))
+	})
+}
+
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < '!' || requestID[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func requestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// clientIP is the address the request came from. Behind a proxy this is the
+// proxy's address.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// auditChange is one field of an audit entry's diff. Old is left out for
+// things that were created and New for things that were removed.
+type auditChange struct {
+	Old any `json:"old,omitempty"`
+	New any `json:"new,omitempty"`
+}
+
+type auditEntry struct {
+	actorType  string
+	actorID    uuid.NullUUID
+	action     string
+	targetType string
+	targetID   uuid.NullUUID
+	diff       map[string]auditChange
+}
+
+// recordAudit appends an entry to the audit log, tagged with the request's ID
+// and the caller's IP address. Pass the queries of the transaction making the
+// change, so the entry is only kept if the change is.
+func recordAudit(r *http.Request, q *database.Queries, entry auditEntry) error {
+	if entry.diff == nil {
+		entry.diff = map[string]auditChange{}
+	}
+
+	diff, err := json.Marshal(entry.diff)
+	if err != nil {
+		return err
+	}
+
+	return q.CreateAuditLogEntry(r.Context(), database.CreateAuditLogEntryParams{
+		ActorType:  entry.actorType,
+		ActorID:    entry.actorID,
+		Action:     entry.action,
+		TargetType: entry.targetType,
+		TargetID:   entry.targetID,
+		RequestID:  requestID(r.Context()),
+		Ip:         clientIP(r),
+		Diff:       diff,
+	})
+}
+
+// handleGetAuditLog lists audit log entries, newest first. With format=csv it
+// exports every matching entry instead of a page.
+func (cfg *apiConfig) handleGetAuditLog(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authorizeAdmin(w, r) {
+		return
+	}
+
+	params, err := auditLogFilters(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		cfg.exportAuditLog(w, r, params)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	params.Limit = limit + 1
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	dbEntries, err := cfg.dbQueries.ListAuditLog(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	page := AuditLogPage{Entries: []AuditLogEntry{}}
+	if len(dbEntries) > int(limit) {
+		dbEntries = dbEntries[:limit]
+		last := dbEntries[len(dbEntries)-1]
+		page.NextCursor = pagination.EncodeCursor(pagination.Cursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+	}
+
+	for _, dbEntry := range dbEntries {
+		page.Entries = append(page.Entries, databaseAuditLogToAuditLogEntry(dbEntry))
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+// exportAuditLog streams the matching entries as CSV. Once the first row is
+// written the status can no longer change, so a later failure cuts the export
+// short and is only logged.
+func (cfg *apiConfig) exportAuditLog(w http.ResponseWriter, r *http.Request, params database.ListAuditLogParams) {
+	params.Limit = auditExportBatchSize
+
+	dbEntries, err := cfg.dbQueries.ListAuditLog(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	csvWriter := csv.NewWriter(w)
+	csvWriter.Write([]string{"id", "created_at", "actor_type", "actor_id", "action", "target_type", "target_id", "request_id", "ip", "diff"})
+
+	for len(dbEntries) > 0 {
+		for _, dbEntry := range dbEntries {
+			csvWriter.Write(csvSafeRow([]string{
+				dbEntry.ID.String(),
+				dbEntry.CreatedAt.Format(time.RFC3339Nano),
+				dbEntry.ActorType,
+				nullUUIDString(dbEntry.ActorID),
+				dbEntry.Action,
+				dbEntry.TargetType,
+				nullUUIDString(dbEntry.TargetID),
+				dbEntry.RequestID,
+				dbEntry.Ip,
+				string(dbEntry.Diff),
+			}))
+		}
+		csvWriter.Flush()
+
+		if len(dbEntries) < auditExportBatchSize {
+			break
+		}
+
+		last := dbEntries[len(dbEntries)-1]
+		params.CursorCreatedAt = sql.NullTime{Time: last.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: last.ID, Valid: true}
+
+		dbEntries, err = cfg.dbQueries.ListAuditLog(r.Context(), params)
+		if err != nil {
+			log.Printf("Error exporting audit log: %v", err)
+			return
+		}
+	}
+
+	err = csvWriter.Error()
+	if err != nil {
+		log.Printf("Error exporting audit log: %v", err)
+	}
+}
+
+// csvSafeRow prefixes cells that a spreadsheet would read as a formula with a
+// quote. Request IDs come from callers, so an export must not be able to run
+// anything when an admin opens it.
+func csvSafeRow(row []string) []string {
+	for i, cell := range row {
+		if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			row[i] = "'" + cell
+		}
+	}
+
+	return row
+}
+
+// auditLogFilters reads the audit log filters from the query string.
+func auditLogFilters(r *http.Request) (database.ListAuditLogParams, error) {
+	query := r.URL.Query()
+	var params database.ListAuditLogParams
+
+	if actorType := query.Get("actor_type"); actorType != "" {
+		params.ActorType = sql.NullString{String: actorType, Valid: true}
+	}
+
+	if action := query.Get("action"); action != "" {
+		params.Action = sql.NullString{String: action, Valid: true}
+	}
+
+	if targetType := query.Get("target_type"); targetType != "" {
+		params.TargetType = sql.NullString{String: targetType, Valid: true}
+	}
+
+	if actorID := query.Get("actor_id"); actorID != "" {
+		id, err := uuid.Parse(actorID)
+		if err != nil {
+			return database.ListAuditLogParams{}, errors.New("Invalid actor_id")
+		}
+		params.ActorID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	if targetID := query.Get("target_id"); targetID != "" {
+		id, err := uuid.Parse(targetID)
+		if err != nil {
+			return database.ListAuditLogParams{}, errors.New("Invalid target_id")
+		}
+		params.TargetID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	if since := query.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return database.ListAuditLogParams{}, errors.New("since must be an RFC 3339 timestamp")
+		}
+		params.Since = sql.NullTime{Time: t.UTC(), Valid: true}
+	}
+
+	if until := query.Get("until"); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return database.ListAuditLogParams{}, errors.New("until must be an RFC 3339 timestamp")
+		}
+		params.Until = sql.NullTime{Time: t.UTC(), Valid: true}
+	}
+
+	return params, nil
+}
+
+func nullUUIDString(id uuid.NullUUID) string {
+	if !id.Valid {
+		return ""
+	}
+
+	return id.UUID.String()
+}
+
+func databaseAuditLogToAuditLogEntry(dbEntry database.AuditLog) AuditLogEntry {
+	entry := AuditLogEntry{
+		ID:         dbEntry.ID,
+		CreatedAt:  dbEntry.CreatedAt,
+		ActorType:  dbEntry.ActorType,
+		Action:     dbEntry.Action,
+		TargetType: dbEntry.TargetType,
+		RequestID:  dbEntry.RequestID,
+		IP:         dbEntry.Ip,
+		Diff:       dbEntry.Diff,
+	}
+
+	if dbEntry.ActorID.Valid {
+		entry.ActorID = &dbEntry.ActorID.UUID
+	}
+
+	if dbEntry.TargetID.Valid {
+		entry.TargetID = &dbEntry.TargetID.UUID
+	}
+
+	return entry
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"net/http"
+	"time"
+)
+
+const (
+	reapInterval  = time.Minute
+	reapBatchSize = 100
+
+	maxAutoDeleteAfterDays = 3650
+)
+
+// handleUpdateAutoDelete sets how many days the caller's chirps live before
+// they are deleted automatically. A null value turns auto-delete off.
+func (cfg *apiConfig) handleUpdateAutoDelete(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	var reqBody struct {
+		AutoDeleteAfterDays *int32 `json:"auto_delete_after_days"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	var autoDeleteAfterDays sql.NullInt32
+	if reqBody.AutoDeleteAfterDays != nil {
+		days := *reqBody.AutoDeleteAfterDays
+		if days < 1 || days > maxAutoDeleteAfterDays {
+			respondWithError(w, http.StatusBadRequest, "auto_delete_after_days must be between 1 and 3650")
+			return
+		}
+		autoDeleteAfterDays = sql.NullInt32{Int32: days, Valid: true}
+	}
+
+	dbUser, err := cfg.dbQueries.UpdateUserAutoDelete(r.Context(), database.UpdateUserAutoDeleteParams{
+		ID:                  userID,
+		AutoDeleteAfterDays: autoDeleteAfterDays,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	apiUser := User{
+		ID:          dbUser.ID,
+		CreatedAt:   dbUser.CreatedAt,
+		UpdatedAt:   dbUser.UpdatedAt,
+		Email:       dbUser.Email,
+		Handle:      dbUser.Handle,
+		IsChirpyRed: dbUser.IsChirpyRed,
+	}
+	if dbUser.AutoDeleteAfterDays.Valid {
+		apiUser.AutoDeleteAfterDays = &dbUser.AutoDeleteAfterDays.Int32
+	}
+
+	respondWithJSON(w, http.StatusOK, apiUser)
+}
+
+// reapChirps permanently removes a batch of chirps that have expired or have
+// outlived their author's auto-delete setting. Like the trash purge, rows are
+// claimed with FOR UPDATE SKIP LOCKED so several instances can reap at once.
+func (cfg *apiConfig) reapChirps(ctx context.Context) (int, error) {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	// Expired chirps and chirps past their author's auto-delete setting are
+	// found through separate indexes, so they are claimed with two queries.
+	chirpIDs, err := qtx.ListExpiredChirps(ctx, reapBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(chirpIDs) < reapBatchSize {
+		autoDeletedIDs, err := qtx.ListAutoDeletedChirps(ctx, int32(reapBatchSize-len(chirpIDs)))
+		if err != nil {
+			return 0, err
+		}
+		chirpIDs = append(chirpIDs, autoDeletedIDs...)
+	}
+
+	var attachments []database.MediaAttachment
+	for _, chirpID := range chirpIDs {
+		reapedAttachments, err := purgeChirp(ctx, qtx, chirpID)
+		if err != nil {
+			return 0, err
+		}
+		attachments = append(attachments, reapedAttachments...)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	cfg.removeMediaFiles(ctx, attachments)
+	return len(chirpIDs), nil
+}
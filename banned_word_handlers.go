@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/chirptext"
+	"github.com/pedroomedicina/chirpy/internal/contentfilter"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Every instance reloads the banned word list this often, so changes made
+// through another instance reach it too.
+const bannedWordsRefreshInterval = time.Minute
+
+var bannedWordActions = map[string]contentfilter.Action{
+	"mask":   contentfilter.Mask,
+	"reject": contentfilter.Reject,
+}
+
+// authorizeAdmin checks the caller's admin API key, responding with an error
+// when it is missing or wrong.
+func (cfg *apiConfig) authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	apiKey, err := auth.GetAPIKey(r.Header)
+	if err != nil || cfg.adminKey == "" || apiKey != cfg.adminKey {
+		respondWithError(w, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return false
+	}
+
+	return true
+}
+
+// refreshBannedWords loads the banned word list into the content filters.
+func (cfg *apiConfig) refreshBannedWords(ctx context.Context) error {
+	dbBannedWords, err := cfg.dbQueries.ListBannedWords(ctx)
+	if err != nil {
+		return err
+	}
+
+	words := make(map[string]contentfilter.Action, len(dbBannedWords))
+	for _, dbBannedWord := range dbBannedWords {
+		words[dbBannedWord.Word] = bannedWordActions[dbBannedWord.Severity]
+	}
+
+	cfg.bannedWords.SetWords(words)
+	return nil
+}
+
+func (cfg *apiConfig) refreshBannedWordsPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(bannedWordsRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := cfg.refreshBannedWords(ctx)
+		if err != nil {
+			log.Printf("Error refreshing banned words: %v", err)
+		}
+	}
+}
+
+// bannedWordsChanged reloads the list after an admin edit. The edit has
+// already been saved, so a failure is only logged; the periodic refresh will
+// pick it up.
+func (cfg *apiConfig) bannedWordsChanged(ctx context.Context) {
+	err := cfg.refreshBannedWords(ctx)
+	if err != nil {
+		log.Printf("Error refreshing banned words: %v", err)
+	}
+}
+
+func (cfg *apiConfig) handleListBannedWords(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authorizeAdmin(w, r) {
+		return
+	}
+
+	dbBannedWords, err := cfg.dbQueries.ListBannedWords(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	bannedWords := []BannedWord{}
+	for _, dbBannedWord := range dbBannedWords {
+		bannedWords = append(bannedWords, databaseBannedWordToBannedWord(dbBannedWord))
+	}
+
+	respondWithJSON(w, http.StatusOK, bannedWords)
+}
+
+func (cfg *apiConfig) handleCreateBannedWord(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authorizeAdmin(w, r) {
+		return
+	}
+
+	var reqBody struct {
+		Word     string `json:"word"`
+		Severity string `json:"severity"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	word := strings.ToLower(strings.TrimSpace(reqBody.Word))
+	if !contentfilter.ValidWord(word) {
+		respondWithError(w, http.StatusBadRequest, "Banned words must be a single word")
+		return
+	}
+
+	if _, ok := bannedWordActions[reqBody.Severity]; !ok {
+		respondWithError(w, http.StatusBadRequest, `Severity must be "mask" or "reject"`)
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	// The filter compares folded words, so a spelling that folds the same as
+	// a banned word, like "k3rfuffle" for "kerfuffle", is the same word. The
+	// table is locked against other writers until the insert commits, so two
+	// such spellings cannot both pass the check.
+	err = qtx.LockBannedWords(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	dbBannedWords, err := qtx.ListBannedWords(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	for _, dbBannedWord := range dbBannedWords {
+		if contentfilter.Fold(dbBannedWord.Word) == contentfilter.Fold(word) {
+			respondWithError(w, http.StatusConflict, "Word is already banned as "+strconv.Quote(dbBannedWord.Word))
+			return
+		}
+	}
+
+	dbBannedWord, err := qtx.CreateBannedWord(r.Context(), database.CreateBannedWordParams{
+		Word:     word,
+		Severity: reqBody.Severity,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusConflict, "Word is already banned")
+			return
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	err = recordAudit(r, qtx, auditEntry{
+		actorType:  auditActorAdmin,
+		action:     auditActionBannedWordCreate,
+		targetType: auditTargetBannedWord,
+		targetID:   uuid.NullUUID{UUID: dbBannedWord.ID, Valid: true},
+		diff: map[string]auditChange{
+			"word":     {New: dbBannedWord.Word},
+			"severity": {New: dbBannedWord.Severity},
+		},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	cfg.bannedWordsChanged(r.Context())
+	respondWithJSON(w, http.StatusCreated, databaseBannedWordToBannedWord(dbBannedWord))
+}
+
+func (cfg *apiConfig) handleUpdateBannedWord(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authorizeAdmin(w, r) {
+		return
+	}
+
+	bannedWordID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid banned word ID")
+		return
+	}
+
+	var reqBody struct {
+		Severity string `json:"severity"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	if _, ok := bannedWordActions[reqBody.Severity]; !ok {
+		respondWithError(w, http.StatusBadRequest, `Severity must be "mask" or "reject"`)
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	oldBannedWord, err := qtx.GetBannedWordByIDForUpdate(r.Context(), bannedWordID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+			return
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	dbBannedWord, err := qtx.UpdateBannedWordSeverity(r.Context(), database.UpdateBannedWordSeverityParams{
+		ID:       bannedWordID,
+		Severity: reqBody.Severity,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	err = recordAudit(r, qtx, auditEntry{
+		actorType:  auditActorAdmin,
+		action:     auditActionBannedWordUpdate,
+		targetType: auditTargetBannedWord,
+		targetID:   uuid.NullUUID{UUID: dbBannedWord.ID, Valid: true},
+		diff: map[string]auditChange{
+			"severity": {Old: oldBannedWord.Severity, New: dbBannedWord.Severity},
+		},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	cfg.bannedWordsChanged(r.Context())
+	respondWithJSON(w, http.StatusOK, databaseBannedWordToBannedWord(dbBannedWord))
+}
+
+func (cfg *apiConfig) handleDeleteBannedWord(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authorizeAdmin(w, r) {
+		return
+	}
+
+	bannedWordID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid banned word ID")
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	dbBannedWord, err := qtx.GetBannedWordByIDForUpdate(r.Context(), bannedWordID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+			return
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	_, err = qtx.DeleteBannedWord(r.Context(), bannedWordID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	err = recordAudit(r, qtx, auditEntry{
+		actorType:  auditActorAdmin,
+		action:     auditActionBannedWordDelete,
+		targetType: auditTargetBannedWord,
+		targetID:   uuid.NullUUID{UUID: dbBannedWord.ID, Valid: true},
+		diff: map[string]auditChange{
+			"word":     {Old: dbBannedWord.Word},
+			"severity": {Old: dbBannedWord.Severity},
+		},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	cfg.bannedWordsChanged(r.Context())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlePreviewContentFilters shows what the content filters would do to a
+// chirp body without posting anything.
+func (cfg *apiConfig) handlePreviewContentFilters(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authorizeAdmin(w, r) {
+		return
+	}
+
+	var reqBody struct {
+		Text string `json:"text"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	filtered := cfg.contentFilters.Apply(chirptext.Normalize(reqBody.Text))
+	preview := ContentFilterPreview{
+		Text:     filtered.Text,
+		Rejected: filtered.Rejected,
+		Reason:   filtered.Reason,
+		Flags:    []ContentFlag{},
+	}
+	for _, flag := range filtered.Flags {
+		preview.Flags = append(preview.Flags, ContentFlag{
+			Filter: flag.Filter,
+			Reason: flag.Reason,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, preview)
+}
+
+func databaseBannedWordToBannedWord(dbBannedWord database.BannedWord) BannedWord {
+	return BannedWord{
+		ID:        dbBannedWord.ID,
+		CreatedAt: dbBannedWord.CreatedAt,
+		UpdatedAt: dbBannedWord.UpdatedAt,
+		Word:      dbBannedWord.Word,
+		Severity:  dbBannedWord.Severity,
+	}
+}
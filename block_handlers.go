@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/pagination"
+	"net/http"
+)
+
+func (cfg *apiConfig) handleBlockUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := cfg.relationshipTarget(w, r, "block")
+	if !ok {
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	_, err = qtx.CreateUserBlock(r.Context(), database.CreateUserBlockParams{
+		BlockerID: userID,
+		BlockedID: targetID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	// A block ends any follow between the two users, in both directions.
+	err = qtx.DeleteFollowsBetween(r.Context(), database.DeleteFollowsBetweenParams{
+		UserID:      userID,
+		OtherUserID: targetID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handleUnblockUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := cfg.relationshipTarget(w, r, "unblock")
+	if !ok {
+		return
+	}
+
+	_, err := cfg.dbQueries.DeleteUserBlock(r.Context(), database.DeleteUserBlockParams{
+		BlockerID: userID,
+		BlockedID: targetID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handleMuteUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := cfg.relationshipTarget(w, r, "mute")
+	if !ok {
+		return
+	}
+
+	_, err := cfg.dbQueries.CreateUserMute(r.Context(), database.CreateUserMuteParams{
+		MuterID: userID,
+		MutedID: targetID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handleUnmuteUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := cfg.relationshipTarget(w, r, "unmute")
+	if !ok {
+		return
+	}
+
+	_, err := cfg.dbQueries.DeleteUserMute(r.Context(), database.DeleteUserMuteParams{
+		MuterID: userID,
+		MutedID: targetID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// relationshipTarget authenticates the caller and resolves the user named in
+// the path. Blocking, muting and following are idempotent, so their handlers
+// respond with 204 whether or not anything changed.
+func (cfg *apiConfig) relationshipTarget(w http.ResponseWriter, r *http.Request, verb string) (uuid.UUID, uuid.UUID, bool) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	targetID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid User ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if targetID == userID {
+		respondWithError(w, http.StatusBadRequest, "You cannot "+verb+" yourself")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	_, err = cfg.dbQueries.GetUserByID(r.Context(), targetID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+			return uuid.Nil, uuid.Nil, false
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, targetID, true
+}
+
+func (cfg *apiConfig) handleGetBlocks(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := database.ListUserBlocksParams{
+		BlockerID: userID,
+		Limit:     limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorBlockedID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	dbBlocks, err := cfg.dbQueries.ListUserBlocks(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	page := RelatedUsersPage{Users: []RelatedUser{}}
+	if len(dbBlocks) > int(limit) {
+		dbBlocks = dbBlocks[:limit]
+		last := dbBlocks[len(dbBlocks)-1]
+		page.NextCursor = pagination.EncodeCursor(pagination.Cursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.BlockedID,
+		})
+	}
+
+	for _, dbBlock := range dbBlocks {
+		page.Users = append(page.Users, RelatedUser{
+			UserID:    dbBlock.BlockedID,
+			CreatedAt: dbBlock.CreatedAt,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+func (cfg *apiConfig) handleGetMutes(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := database.ListUserMutesParams{
+		MuterID: userID,
+		Limit:   limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorMutedID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	dbMutes, err := cfg.dbQueries.ListUserMutes(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	page := RelatedUsersPage{Users: []RelatedUser{}}
+	if len(dbMutes) > int(limit) {
+		dbMutes = dbMutes[:limit]
+		last := dbMutes[len(dbMutes)-1]
+		page.NextCursor = pagination.EncodeCursor(pagination.Cursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.MutedID,
+		})
+	}
+
+	for _, dbMute := range dbMutes {
+		page.Users = append(page.Users, RelatedUser{
+			UserID:    dbMute.MutedID,
+			CreatedAt: dbMute.CreatedAt,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+// blockedBetween reports whether either user has blocked the other. Anonymous
+// viewers are never blocked.
+func (cfg *apiConfig) blockedBetween(ctx context.Context, viewerID uuid.NullUUID, userID uuid.UUID) (bool, error) {
+	if !viewerID.Valid || viewerID.UUID == userID {
+		return false, nil
+	}
+
+	return cfg.dbQueries.HasBlockBetween(ctx, database.HasBlockBetweenParams{
+		UserID:      viewerID.UUID,
+		OtherUserID: userID,
+	})
+}
+
+// hiddenAuthors returns the users whose chirps are kept from the viewer: the
+// ones the viewer blocked or muted and the ones who blocked the viewer.
+func (cfg *apiConfig) hiddenAuthors(ctx context.Context, viewerID uuid.NullUUID) (map[uuid.UUID]bool, error) {
+	hidden := make(map[uuid.UUID]bool)
+	if !viewerID.Valid {
+		return hidden, nil
+	}
+
+	authorIDs, err := cfg.dbQueries.GetHiddenAuthors(ctx, viewerID.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, authorID := range authorIDs {
+		hidden[authorID] = true
+	}
+
+	return hidden, nil
+}
@@ -1,15 +1,21 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/chirptext"
 	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/pagination"
+	"github.com/pedroomedicina/chirpy/internal/spam"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 )
 
 func (cfg *apiConfig) handleCreateChirp(w http.ResponseWriter, r *http.Request) {
@@ -24,9 +30,19 @@ func (cfg *apiConfig) handleCreateChirp(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	userID, err := auth.ValidateJWT(tokenString, cfg.jwtSecret)
+	userID, err := cfg.authenticate(r.Context(), tokenString)
 	if err != nil {
-		respondWithError(w, http.StatusUnauthorized, "Invalid or expired token")
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limits, err := cfg.limitsFor(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if !cfg.allowWrite(w, userID, limits) {
 		return
 	}
 
@@ -36,32 +52,242 @@ func (cfg *apiConfig) handleCreateChirp(w http.ResponseWriter, r *http.Request)
 		respondWithError(w, http.StatusBadRequest, "Invalid JSON body")
 		return
 	}
+	chirp.Body = chirptext.Normalize(chirp.Body)
 
-	err = cfg.validateChirp(chirp)
+	err = cfg.validateChirp(chirp, limits)
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	recentChirps, err := cfg.dbQueries.CountRecentChirps(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if recentChirps >= int64(limits.DailyChirpQuota) {
+		respondWithError(w, http.StatusTooManyRequests, "Daily chirp limit reached")
+		return
+	}
+
+	var parentChirpID uuid.NullUUID
+	if chirp.InReplyTo != nil {
+		parentChirp, err := cfg.dbQueries.GetChirpByID(r.Context(), *chirp.InReplyTo)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusBadRequest, "Chirp being replied to does not exist")
+				return
+			}
+
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+
+		if parentChirp.PublishAt.Valid {
+			respondWithError(w, http.StatusBadRequest, "Chirp being replied to does not exist")
+			return
+		}
+
+		if chirpRemoved(parentChirp) {
+			respondWithError(w, http.StatusBadRequest, "Chirp being replied to has been deleted")
+			return
+		}
+
+		blocked, err := cfg.blockedBetween(r.Context(), uuid.NullUUID{UUID: userID, Valid: true}, parentChirp.UserID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+
+		if blocked {
+			respondWithError(w, http.StatusForbidden, "You cannot reply to this user")
+			return
+		}
+		parentChirpID = uuid.NullUUID{UUID: parentChirp.ID, Valid: true}
+	}
+
+	var quotedChirpID uuid.NullUUID
+	if chirp.QuotedChirpID != nil {
+		quotedChirp, err := cfg.dbQueries.GetChirpByID(r.Context(), *chirp.QuotedChirpID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusBadRequest, "Quoted chirp does not exist")
+				return
+			}
+
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+
+		if quotedChirp.PublishAt.Valid {
+			respondWithError(w, http.StatusBadRequest, "Quoted chirp does not exist")
+			return
+		}
+
+		if chirpRemoved(quotedChirp) {
+			respondWithError(w, http.StatusBadRequest, "Quoted chirp has been deleted")
+			return
+		}
+		quotedChirpID = uuid.NullUUID{UUID: quotedChirp.ID, Valid: true}
+	}
+
+	var publishAt sql.NullTime
+	if chirp.PublishAt != nil {
+		if !chirp.PublishAt.After(time.Now()) {
+			respondWithError(w, http.StatusBadRequest, "publish_at must be in the future")
+			return
+		}
+		publishAt = sql.NullTime{Time: chirp.PublishAt.UTC(), Valid: true}
 	}
 
-	cleanedChirpBody := cleanProfanity(chirp.Body)
+	var expiresAt sql.NullTime
+	if chirp.ExpiresAt != nil {
+		if !chirp.ExpiresAt.After(time.Now()) || (publishAt.Valid && !chirp.ExpiresAt.After(publishAt.Time)) {
+			respondWithError(w, http.StatusBadRequest, "expires_at must be after the chirp is published")
+			return
+		}
+		expiresAt = sql.NullTime{Time: chirp.ExpiresAt.UTC(), Valid: true}
+	}
+
+	filtered := cfg.contentFilters.Apply(chirp.Body)
+	if filtered.Rejected {
+		respondWithError(w, http.StatusBadRequest, filtered.Reason)
+		return
+	}
+	cleanedChirpBody := filtered.Text
 
-	dbChirp, err := cfg.dbQueries.CreateChirp(r.Context(), database.CreateChirpParams{
-		Body:   cleanedChirpBody,
-		UserID: userID,
+	spamResult, err := cfg.scoreChirp(r.Context(), userID, uuid.NullUUID{}, cleanedChirpBody, len(filtered.Flags))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if spamResult.Decision == spam.Reject {
+		log.Printf("Rejected chirp from user %s: %s", userID, spamNote(spamResult))
+		respondWithError(w, http.StatusBadRequest, "Chirp looks like spam")
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	dbChirp, err := qtx.CreateChirp(r.Context(), database.CreateChirpParams{
+		Body:          cleanedChirpBody,
+		UserID:        userID,
+		ParentChirpID: parentChirpID,
+		QuotedChirpID: quotedChirpID,
+		PublishAt:     publishAt,
+		ExpiresAt:     expiresAt,
 	})
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
-	apiChirp := Chirp{
-		ID:        dbChirp.ID,
-		CreatedAt: dbChirp.CreatedAt,
-		UpdatedAt: dbChirp.UpdatedAt,
-		Body:      dbChirp.Body,
-		UserID:    dbChirp.UserID,
+	// Scheduled chirps get their hashtags and mentions when they are published.
+	if !dbChirp.PublishAt.Valid {
+		err = saveChirpEntities(r.Context(), qtx, dbChirp)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
 	}
 
-	respondWithJSON(w, http.StatusCreated, apiChirp)
+	err = attachChirpMedia(r.Context(), qtx, dbChirp.ID, userID, chirp.Media)
+	if err != nil {
+		if errors.Is(err, errInvalidMediaAttachment) {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	held := spamResult.Decision == spam.Hold
+	if held {
+		err = holdChirpForSpam(r.Context(), qtx, dbChirp, spamResult)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	logContentFlags(dbChirp.ID, filtered.Flags)
+
+	chirps, err := cfg.chirpsForResponse(r.Context(), uuid.NullUUID{UUID: userID, Valid: true}, []database.Chirp{dbChirp})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	remaining := remainingCharacters(chirps[0].Body, limits)
+	chirps[0].RemainingCharacters = &remaining
+
+	// Held chirps stay hidden until a moderator dismisses the spam report.
+	if held {
+		chirps[0].HeldForModeration = true
+		respondWithJSON(w, http.StatusAccepted, chirps[0])
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, chirps[0])
+}
+
+func (cfg *apiConfig) handleValidateChirp(w http.ResponseWriter, r *http.Request) {
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), tokenString)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limits, err := cfg.limitsFor(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	var chirp Chirp
+	err = json.NewDecoder(r.Body).Decode(&chirp)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	chirp.Body = chirptext.Normalize(chirp.Body)
+
+	validation := ChirpValidation{
+		Valid:               true,
+		Length:              chirptext.Length(chirp.Body),
+		RemainingCharacters: remainingCharacters(chirp.Body, limits),
+	}
+
+	err = cfg.validateChirp(chirp, limits)
+	if err != nil {
+		validation.Valid = false
+		validation.Error = err.Error()
+	} else if filtered := cfg.contentFilters.Apply(chirp.Body); filtered.Rejected {
+		validation.Valid = false
+		validation.Error = filtered.Reason
+	}
+
+	respondWithJSON(w, http.StatusOK, validation)
 }
 
 func validateSortDirection(sort string) (string, error) {
@@ -78,17 +304,12 @@ func validateSortDirection(sort string) (string, error) {
 	return "", fmt.Errorf("invalid sort direction: %s", sort)
 }
 
-func scanChirps(rows *sql.Rows) ([]Chirp, error) {
-	var chirps []Chirp
-	for rows.Next() {
-		var chirp Chirp
-		err := rows.Scan(&chirp.ID, &chirp.CreatedAt, &chirp.UpdatedAt, &chirp.Body, &chirp.UserID)
-		if err != nil {
-			return nil, err
-		}
-		chirps = append(chirps, chirp)
+func (cfg *apiConfig) listChirps(ctx context.Context, sort string, params database.ListChirpsAscParams) ([]database.Chirp, error) {
+	if sort == "DESC" {
+		return cfg.dbQueries.ListChirpsDesc(ctx, database.ListChirpsDescParams(params))
 	}
-	return chirps, rows.Err()
+
+	return cfg.dbQueries.ListChirpsAsc(ctx, params)
 }
 
 func (cfg *apiConfig) handleGetAllChirps(w http.ResponseWriter, r *http.Request) {
@@ -100,6 +321,19 @@ func (cfg *apiConfig) handleGetAllChirps(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// One extra row is fetched to know whether another page follows.
+	viewerID := cfg.viewerID(r)
+	params := database.ListChirpsAscParams{
+		ViewerID: viewerID,
+		Limit:    limit + 1,
+	}
+
 	authorId := r.URL.Query().Get("author_id")
 	if authorId != "" {
 		authorUuid, err := uuid.Parse(authorId)
@@ -107,61 +341,181 @@ func (cfg *apiConfig) handleGetAllChirps(w http.ResponseWriter, r *http.Request)
 			respondWithError(w, http.StatusBadRequest, err.Error())
 			return
 		}
+		params.AuthorID = uuid.NullUUID{UUID: authorUuid, Valid: true}
+	}
 
-		query := fmt.Sprintf("SELECT * FROM chirps WHERE user_id = '%s' ORDER BY created_at %s", authorUuid, sortQueryParam)
-		rows, err := cfg.db.QueryContext(r.Context(), query)
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
 		if err != nil {
-			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			respondWithError(w, http.StatusBadRequest, err.Error())
 			return
 		}
-		defer func(rows *sql.Rows) {
-			err := rows.Close()
-			if err != nil {
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
 
-			}
-		}(rows)
+	dbChirps, err := cfg.listChirps(r.Context(), validatedSort, params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	page, err := cfg.newChirpsPage(r.Context(), viewerID, dbChirps, limit, muteScopeTimeline)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+func (cfg *apiConfig) handleSearchChirps(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		respondWithError(w, http.StatusBadRequest, "Missing search query")
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-		chirps, err := scanChirps(rows)
+	var authorID uuid.NullUUID
+	authorId := r.URL.Query().Get("author_id")
+	if authorId != "" {
+		authorUuid, err := uuid.Parse(authorId)
 		if err != nil {
-			respondWithError(w, http.StatusInternalServerError, err.Error())
+			respondWithError(w, http.StatusBadRequest, err.Error())
 			return
 		}
+		authorID = uuid.NullUUID{UUID: authorUuid, Valid: true}
+	}
 
-		respondWithJSON(w, http.StatusOK, chirps)
+	// Results are ranked by relevance unless the caller asks for a chronological sort.
+	sortQueryParam := r.URL.Query().Get("sort")
+	if sortQueryParam == "" {
+		cfg.searchChirpsByRank(w, r, query, authorID, limit)
 		return
 	}
 
-	query := fmt.Sprintf("SELECT * FROM chirps ORDER BY created_at %s", sortQueryParam)
-	rows, err := cfg.db.QueryContext(r.Context(), query)
+	validatedSort, err := validateSortDirection(sortQueryParam)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	defer func(rows *sql.Rows) {
-		err := rows.Close()
+
+	viewerID := cfg.viewerID(r)
+	params := database.SearchChirpsAscParams{
+		Query:    query,
+		AuthorID: authorID,
+		ViewerID: viewerID,
+		Limit:    limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
 		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	var dbChirps []database.Chirp
+	if validatedSort == "DESC" {
+		dbChirps, err = cfg.dbQueries.SearchChirpsDesc(r.Context(), database.SearchChirpsDescParams(params))
+	} else {
+		dbChirps, err = cfg.dbQueries.SearchChirpsAsc(r.Context(), params)
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	page, err := cfg.newChirpsPage(r.Context(), viewerID, dbChirps, limit, muteScopeEverywhere)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+func (cfg *apiConfig) searchChirpsByRank(w http.ResponseWriter, r *http.Request, query string, authorID uuid.NullUUID, limit int32) {
+	viewerID := cfg.viewerID(r)
+	params := database.SearchChirpsByRankParams{
+		Query:    query,
+		AuthorID: authorID,
+		ViewerID: viewerID,
+		Limit:    limit + 1,
+	}
 
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeRankCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
 		}
-	}(rows)
+		params.CursorRank = sql.NullFloat64{Float64: float64(cursor.Rank), Valid: true}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
 
-	chirps, err := scanChirps(rows)
+	rows, err := cfg.dbQueries.SearchChirpsByRank(r.Context(), params)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, err.Error())
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
-	var apiChirps []Chirp
-	for _, chirp := range chirps {
-		apiChirps = append(apiChirps, Chirp{
-			ID:        chirp.ID,
-			CreatedAt: chirp.CreatedAt,
-			UpdatedAt: chirp.UpdatedAt,
-			Body:      chirp.Body,
-			UserID:    chirp.UserID,
+	var nextCursor string
+	if len(rows) > int(limit) {
+		rows = rows[:limit]
+		last := rows[len(rows)-1]
+		nextCursor = pagination.EncodeRankCursor(pagination.RankCursor{
+			Rank: last.Rank,
+			Cursor: pagination.Cursor{
+				CreatedAt: last.CreatedAt,
+				ID:        last.ID,
+			},
+		})
+	}
+
+	var dbChirps []database.Chirp
+	for _, row := range rows {
+		dbChirps = append(dbChirps, database.Chirp{
+			ID:            row.ID,
+			CreatedAt:     row.CreatedAt,
+			UpdatedAt:     row.UpdatedAt,
+			Body:          row.Body,
+			UserID:        row.UserID,
+			ParentChirpID: row.ParentChirpID,
+			QuotedChirpID: row.QuotedChirpID,
+			ExpiresAt:     row.ExpiresAt,
 		})
 	}
 
-	respondWithJSON(w, http.StatusOK, apiChirps)
+	dbChirps, err = cfg.filterMutedChirps(r.Context(), viewerID, muteScopeEverywhere, dbChirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	chirps, err := cfg.chirpsForResponse(r.Context(), viewerID, dbChirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, ChirpsPage{
+		Chirps:     chirps,
+		NextCursor: nextCursor,
+	})
 }
 
 func (cfg *apiConfig) handleGetChirpByID(w http.ResponseWriter, r *http.Request) {
@@ -182,15 +536,262 @@ func (cfg *apiConfig) handleGetChirpByID(w http.ResponseWriter, r *http.Request)
 		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
-	respondWithJSON(w, http.StatusOK, Chirp{
-		ID:        dbChirp.ID,
-		CreatedAt: dbChirp.CreatedAt,
-		UpdatedAt: dbChirp.UpdatedAt,
-		Body:      dbChirp.Body,
-		UserID:    dbChirp.UserID,
+
+	viewerID := cfg.viewerID(r)
+	if chirpRemoved(dbChirp) || hiddenScheduledChirp(dbChirp, viewerID) {
+		respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return
+	}
+
+	hidden, err := cfg.authorHiddenFrom(r.Context(), viewerID, dbChirp.UserID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if hidden {
+		respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return
+	}
+
+	chirps, err := cfg.chirpsForResponse(r.Context(), viewerID, []database.Chirp{dbChirp})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, chirps[0])
+}
+
+func (cfg *apiConfig) handleUpdateChirp(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	chirpIDString := r.PathValue("id")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid Chirp ID")
+		return
+	}
+
+	limits, err := cfg.limitsFor(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if !cfg.allowWrite(w, userID, limits) {
+		return
+	}
+
+	var chirp Chirp
+	err = json.NewDecoder(r.Body).Decode(&chirp)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	chirp.Body = chirptext.Normalize(chirp.Body)
+
+	err = cfg.validateChirp(chirp, limits)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filtered := cfg.contentFilters.Apply(chirp.Body)
+	if filtered.Rejected {
+		respondWithError(w, http.StatusBadRequest, filtered.Reason)
+		return
+	}
+	cleanedChirpBody := filtered.Text
+
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	dbChirp, err := qtx.GetChirpByIDForUpdate(r.Context(), chirpID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+			return
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if chirpRemoved(dbChirp) || hiddenScheduledChirp(dbChirp, uuid.NullUUID{UUID: userID, Valid: true}) {
+		respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return
+	}
+
+	if dbChirp.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "you are not authorized to edit this chirp")
+		return
+	}
+
+	// The edit window only starts once a scheduled chirp is published.
+	if !dbChirp.PublishAt.Valid && time.Since(dbChirp.CreatedAt) > limits.EditWindow {
+		respondWithError(w, http.StatusForbidden, "the edit window for this chirp has expired")
+		return
+	}
+
+	if cleanedChirpBody == dbChirp.Body {
+		chirps, err := cfg.chirpsForResponse(r.Context(), uuid.NullUUID{UUID: userID, Valid: true}, []database.Chirp{dbChirp})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+
+		remaining := remainingCharacters(chirps[0].Body, limits)
+		chirps[0].RemainingCharacters = &remaining
+		respondWithJSON(w, http.StatusOK, chirps[0])
+		return
+	}
+
+	// Edits are scored like new chirps, so spam cannot be edited into a
+	// chirp that passed when it was posted.
+	spamResult, err := cfg.scoreChirp(r.Context(), userID, uuid.NullUUID{UUID: dbChirp.ID, Valid: true}, cleanedChirpBody, len(filtered.Flags))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if spamResult.Decision == spam.Reject {
+		log.Printf("Rejected edit of chirp %s from user %s: %s", dbChirp.ID, userID, spamNote(spamResult))
+		respondWithError(w, http.StatusBadRequest, "Chirp looks like spam")
+		return
+	}
+
+	_, err = qtx.CreateChirpRevision(r.Context(), database.CreateChirpRevisionParams{
+		ChirpID: dbChirp.ID,
+		Body:    dbChirp.Body,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	updatedChirp, err := qtx.UpdateChirpBody(r.Context(), database.UpdateChirpBodyParams{
+		ID:   dbChirp.ID,
+		Body: cleanedChirpBody,
 	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if !updatedChirp.PublishAt.Valid {
+		err = saveChirpEntities(r.Context(), qtx, updatedChirp)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	held := spamResult.Decision == spam.Hold
+	if held {
+		err = holdChirpForSpam(r.Context(), qtx, updatedChirp, spamResult)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	logContentFlags(updatedChirp.ID, filtered.Flags)
+
+	chirps, err := cfg.chirpsForResponse(r.Context(), uuid.NullUUID{UUID: userID, Valid: true}, []database.Chirp{updatedChirp})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	remaining := remainingCharacters(chirps[0].Body, limits)
+	chirps[0].RemainingCharacters = &remaining
+
+	if held {
+		chirps[0].HeldForModeration = true
+		respondWithJSON(w, http.StatusAccepted, chirps[0])
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, chirps[0])
 }
 
+func (cfg *apiConfig) handleGetChirpRevisions(w http.ResponseWriter, r *http.Request) {
+	chirpIDString := r.PathValue("id")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid Chirp ID")
+		return
+	}
+
+	dbChirp, err := cfg.dbQueries.GetChirpByID(r.Context(), chirpID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+			return
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if chirpRemoved(dbChirp) || hiddenScheduledChirp(dbChirp, cfg.viewerID(r)) {
+		respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return
+	}
+
+	hidden, err := cfg.authorHiddenFrom(r.Context(), cfg.viewerID(r), dbChirp.UserID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if hidden {
+		respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return
+	}
+
+	dbRevisions, err := cfg.dbQueries.GetChirpRevisions(r.Context(), chirpID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	revisions := []ChirpRevision{}
+	for _, dbRevision := range dbRevisions {
+		revisions = append(revisions, ChirpRevision{
+			ID:        dbRevision.ID,
+			CreatedAt: dbRevision.CreatedAt,
+			ChirpID:   dbRevision.ChirpID,
+			Body:      dbRevision.Body,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, revisions)
+}
+
+// handleDeleteChirp moves one of the caller's chirps to the trash, where it can
+// be restored until the purge job removes it for good.
 func (cfg *apiConfig) handleDeleteChirp(w http.ResponseWriter, r *http.Request) {
 	accessToken, err := auth.GetBearerToken(r.Header)
 	if err != nil {
@@ -198,9 +799,9 @@ func (cfg *apiConfig) handleDeleteChirp(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	userID, err := auth.ValidateJWT(accessToken, cfg.jwtSecret)
+	userID, err := cfg.authenticate(r.Context(), accessToken)
 	if err != nil {
-		respondWithError(w, http.StatusUnauthorized, "Invalid or expired token")
+		respondWithAuthError(w, err)
 		return
 	}
 
@@ -222,12 +823,17 @@ func (cfg *apiConfig) handleDeleteChirp(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if chirpRemoved(dbChirp) || hiddenScheduledChirp(dbChirp, uuid.NullUUID{UUID: userID, Valid: true}) {
+		respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return
+	}
+
 	if dbChirp.UserID != userID {
 		respondWithError(w, http.StatusForbidden, "you are not authorized to delete this chirp")
 		return
 	}
 
-	err = cfg.dbQueries.DeleteChirp(r.Context(), dbChirp.ID)
+	err = cfg.dbQueries.SoftDeleteChirp(r.Context(), dbChirp.ID)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
@@ -235,3 +841,102 @@ func (cfg *apiConfig) handleDeleteChirp(w http.ResponseWriter, r *http.Request)
 
 	respondWithJSON(w, http.StatusNoContent, nil)
 }
+
+// saveChirpEntities stores the hashtags and mentions found in a chirp's body.
+func saveChirpEntities(ctx context.Context, q *database.Queries, dbChirp database.Chirp) error {
+	err := saveChirpHashtags(ctx, q, dbChirp)
+	if err != nil {
+		return err
+	}
+
+	return saveChirpMentions(ctx, q, dbChirp)
+}
+
+// purgeChirp permanently removes a trashed chirp. Rechirps of the chirp are
+// removed along with it. Quote chirps are kept: they lose the reference when
+// the chirp is deleted outright, or point at a tombstone when the chirp still
+// has replies. The returned media files are left for the caller to remove once
+// the transaction commits.
+func purgeChirp(ctx context.Context, q *database.Queries, chirpID uuid.UUID) ([]database.MediaAttachment, error) {
+	replyCount, err := q.CountChirpReplies(ctx, uuid.NullUUID{UUID: chirpID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := q.DeleteMediaAttachmentsForChirp(ctx, uuid.NullUUID{UUID: chirpID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	if replyCount == 0 {
+		return attachments, q.DeleteChirp(ctx, chirpID)
+	}
+
+	return attachments, tombstoneChirp(ctx, q, chirpID)
+}
+
+// tombstoneChirp blanks a chirp that still has replies instead of deleting it,
+// so its replies stay reachable through the thread view.
+func tombstoneChirp(ctx context.Context, q *database.Queries, chirpID uuid.UUID) error {
+	err := q.TombstoneChirp(ctx, chirpID)
+	if err != nil {
+		return err
+	}
+
+	err = q.DeleteChirpRevisions(ctx, chirpID)
+	if err != nil {
+		return err
+	}
+
+	err = q.DeleteRechirpsForChirp(ctx, chirpID)
+	if err != nil {
+		return err
+	}
+
+	err = q.DeleteChirpHashtags(ctx, chirpID)
+	if err != nil {
+		return err
+	}
+
+	return q.DeleteChirpMentions(ctx, chirpID)
+}
+
+// lookupChirp resolves the {id} path value to a published chirp that has not
+// been removed, responding with the appropriate error when it cannot.
+func (cfg *apiConfig) lookupChirp(w http.ResponseWriter, r *http.Request) (database.Chirp, bool) {
+	chirpIDString := r.PathValue("id")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid Chirp ID")
+		return database.Chirp{}, false
+	}
+
+	dbChirp, err := cfg.dbQueries.GetChirpByID(r.Context(), chirpID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+			return database.Chirp{}, false
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return database.Chirp{}, false
+	}
+
+	if chirpRemoved(dbChirp) || dbChirp.PublishAt.Valid {
+		respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return database.Chirp{}, false
+	}
+
+	hidden, err := cfg.authorHiddenFrom(r.Context(), cfg.viewerID(r), dbChirp.UserID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return database.Chirp{}, false
+	}
+
+	if hidden {
+		respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return database.Chirp{}, false
+	}
+
+	return dbChirp, true
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/entities"
+	"github.com/pedroomedicina/chirpy/internal/handles"
+	"github.com/pedroomedicina/chirpy/internal/pagination"
+	"time"
+)
+
+// newChirpsPage builds a page from one more chirp than the limit, leaving out
+// the chirps the viewer's mute rules for scope match.
+func (cfg *apiConfig) newChirpsPage(ctx context.Context, viewerID uuid.NullUUID, dbChirps []database.Chirp, limit int32, scope string) (ChirpsPage, error) {
+	var nextCursor string
+	if len(dbChirps) > int(limit) {
+		dbChirps = dbChirps[:limit]
+		last := dbChirps[len(dbChirps)-1]
+		nextCursor = pagination.EncodeCursor(pagination.Cursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+	}
+
+	dbChirps, err := cfg.filterMutedChirps(ctx, viewerID, scope, dbChirps)
+	if err != nil {
+		return ChirpsPage{}, err
+	}
+
+	chirps, err := cfg.chirpsForResponse(ctx, viewerID, dbChirps)
+	if err != nil {
+		return ChirpsPage{}, err
+	}
+
+	return ChirpsPage{
+		Chirps:     chirps,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// chirpsForResponse converts chirps for a response and fills in their
+// engagement counts, media attachments and mentioned users, with one query
+// each for the whole batch. liked_by_me is only filled in when there is a viewer.
+func (cfg *apiConfig) chirpsForResponse(ctx context.Context, viewerID uuid.NullUUID, dbChirps []database.Chirp) ([]Chirp, error) {
+	chirps := []Chirp{}
+	if len(dbChirps) == 0 {
+		return chirps, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(dbChirps))
+	for _, dbChirp := range dbChirps {
+		ids = append(ids, dbChirp.ID)
+	}
+
+	counts, err := cfg.dbQueries.GetChirpCounts(ctx, database.GetChirpCountsParams{
+		ViewerID: viewerID,
+		Ids:      ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	countsByID := make(map[uuid.UUID]database.GetChirpCountsRow, len(counts))
+	for _, count := range counts {
+		countsByID[count.ID] = count
+	}
+
+	attachments, err := cfg.dbQueries.GetMediaAttachmentsForChirps(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaByChirpID := make(map[uuid.UUID][]MediaAttachment, len(dbChirps))
+	for _, attachment := range attachments {
+		mediaByChirpID[attachment.ChirpID.UUID] = append(mediaByChirpID[attachment.ChirpID.UUID], cfg.databaseMediaToMedia(attachment))
+	}
+
+	mentionedUsers, err := cfg.dbQueries.GetMentionedUsersForChirps(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	mentionedUsersByChirpID := make(map[uuid.UUID]map[string]uuid.UUID, len(dbChirps))
+	for _, mentionedUser := range mentionedUsers {
+		if mentionedUsersByChirpID[mentionedUser.ChirpID] == nil {
+			mentionedUsersByChirpID[mentionedUser.ChirpID] = make(map[string]uuid.UUID)
+		}
+		mentionedUsersByChirpID[mentionedUser.ChirpID][handles.Fold(mentionedUser.Handle)] = mentionedUser.UserID
+	}
+
+	for _, dbChirp := range dbChirps {
+		chirp := databaseChirpToChirp(dbChirp)
+		chirp.Entities = chirpEntities(dbChirp.Body, mentionedUsersByChirpID[dbChirp.ID])
+		chirp.RechirpCount = countsByID[dbChirp.ID].RechirpCount
+		chirp.QuoteCount = countsByID[dbChirp.ID].QuoteCount
+		chirp.LikeCount = countsByID[dbChirp.ID].LikeCount
+		if media, ok := mediaByChirpID[dbChirp.ID]; ok {
+			chirp.Media = media
+		}
+		if viewerID.Valid {
+			likedByMe := countsByID[dbChirp.ID].LikedByMe
+			chirp.LikedByMe = &likedByMe
+		}
+		chirps = append(chirps, chirp)
+	}
+
+	return chirps, nil
+}
+
+func databaseChirpToChirp(dbChirp database.Chirp) Chirp {
+	chirp := Chirp{
+		ID:        dbChirp.ID,
+		CreatedAt: dbChirp.CreatedAt,
+		UpdatedAt: dbChirp.UpdatedAt,
+		Body:      dbChirp.Body,
+		UserID:    dbChirp.UserID,
+		Deleted:   dbChirp.TombstonedAt.Valid,
+		Media:     []MediaAttachment{},
+	}
+
+	if dbChirp.ParentChirpID.Valid {
+		chirp.InReplyTo = &dbChirp.ParentChirpID.UUID
+	}
+
+	if dbChirp.QuotedChirpID.Valid {
+		chirp.QuotedChirpID = &dbChirp.QuotedChirpID.UUID
+	}
+
+	if dbChirp.PublishAt.Valid {
+		chirp.PublishAt = &dbChirp.PublishAt.Time
+	}
+
+	if dbChirp.DeletedAt.Valid {
+		chirp.DeletedAt = &dbChirp.DeletedAt.Time
+	}
+
+	if dbChirp.ExpiresAt.Valid {
+		chirp.ExpiresAt = &dbChirp.ExpiresAt.Time
+	}
+
+	return chirp
+}
+
+// chirpEntities locates the entities in a stored chirp body. Mentions carry the
+// mentioned user's ID once the mention has been resolved, which for scheduled
+// chirps only happens when they are published. The handle written in the chirp
+// keeps its user after they change handles.
+func chirpEntities(body string, mentionedUsers map[string]uuid.UUID) *ChirpEntities {
+	chirpEntities := &ChirpEntities{
+		URLs:     []URLEntity{},
+		Hashtags: []HashtagEntity{},
+		Mentions: []MentionEntity{},
+	}
+
+	for _, url := range entities.ExtractURLs(body) {
+		chirpEntities.URLs = append(chirpEntities.URLs, URLEntity{
+			URL:   url.Text,
+			Start: url.Start,
+			End:   url.End,
+		})
+	}
+
+	for _, hashtag := range entities.ExtractHashtags(body) {
+		chirpEntities.Hashtags = append(chirpEntities.Hashtags, HashtagEntity{
+			Tag:   entities.NormalizeHashtag(hashtag.Text),
+			Start: hashtag.Start,
+			End:   hashtag.End,
+		})
+	}
+
+	for _, mention := range entities.ExtractMentions(body) {
+		mentionEntity := MentionEntity{
+			Handle: handles.Fold(mention.Text),
+			Start:  mention.Start,
+			End:    mention.End,
+		}
+		if userID, ok := mentionedUsers[mentionEntity.Handle]; ok {
+			mentionEntity.UserID = &userID
+		}
+		chirpEntities.Mentions = append(chirpEntities.Mentions, mentionEntity)
+	}
+
+	return chirpEntities
+}
+
+// hiddenScheduledChirp reports whether a chirp is still waiting to be
+// published and the viewer is not its author.
+func hiddenScheduledChirp(dbChirp database.Chirp, viewerID uuid.NullUUID) bool {
+	return dbChirp.PublishAt.Valid && (!viewerID.Valid || viewerID.UUID != dbChirp.UserID)
+}
+
+// chirpRemoved reports whether a chirp is in the trash, has expired, has been
+// hidden by moderation or has been reduced to a tombstone, any of which hides
+// it from direct lookups.
+func chirpRemoved(dbChirp database.Chirp) bool {
+	expired := dbChirp.ExpiresAt.Valid && !dbChirp.ExpiresAt.Time.After(time.Now())
+	return dbChirp.TombstonedAt.Valid || dbChirp.DeletedAt.Valid || dbChirp.HiddenAt.Valid || expired
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/contentfilter"
+	"log"
+)
+
+// logContentFlags records why the content filters flagged a chirp that they
+// let through.
+func logContentFlags(chirpID uuid.UUID, flags []contentfilter.FlagResult) {
+	for _, flag := range flags {
+		log.Printf("Chirp %s flagged by the %s filter: %s", chirpID, flag.Filter, flag.Reason)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/entitlements"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// limitsFor looks up the limits of the plan the user is on.
+func (cfg *apiConfig) limitsFor(ctx context.Context, userID uuid.UUID) (entitlements.Limits, error) {
+	dbUser, err := cfg.dbQueries.GetUserByID(ctx, userID)
+	if err != nil {
+		return entitlements.Limits{}, err
+	}
+
+	return cfg.plans.For(dbUser.IsChirpyRed), nil
+}
+
+// allowWrite counts a write against the user's per-minute limit, responding
+// with 429 and a Retry-After header once the limit is used up.
+func (cfg *apiConfig) allowWrite(w http.ResponseWriter, userID uuid.UUID, limits entitlements.Limits) bool {
+	allowed, retryAfter := cfg.writeLimiter.Allow(userID.String(), limits.WritesPerMinute, time.Now())
+	if !allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		respondWithError(w, http.StatusTooManyRequests, "Too many requests, slow down")
+		return false
+	}
+
+	return true
+}
+
+func (cfg *apiConfig) handleGetEntitlements(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limits, err := cfg.limitsFor(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	recentChirps, err := cfg.dbQueries.CountRecentChirps(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Entitlements{
+		Plan:                 string(limits.Plan),
+		MaxChirpLength:       limits.MaxChirpLength,
+		MaxMediaPerChirp:     limits.MaxMediaPerChirp,
+		EditWindowSeconds:    int(limits.EditWindow.Seconds()),
+		DailyChirpQuota:      limits.DailyChirpQuota,
+		RemainingDailyChirps: max(0, limits.DailyChirpQuota-int(recentChirps)),
+		WritesPerMinute:      limits.WritesPerMinute,
+	})
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/pagination"
+	"net/http"
+)
+
+func (cfg *apiConfig) handleFollowUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := cfg.relationshipTarget(w, r, "follow")
+	if !ok {
+		return
+	}
+
+	blocked, err := cfg.blockedBetween(r.Context(), uuid.NullUUID{UUID: userID, Valid: true}, targetID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if blocked {
+		respondWithError(w, http.StatusForbidden, "You cannot follow this user")
+		return
+	}
+
+	_, err = cfg.dbQueries.CreateFollow(r.Context(), database.CreateFollowParams{
+		FollowerID: userID,
+		FollowedID: targetID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handleUnfollowUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := cfg.relationshipTarget(w, r, "unfollow")
+	if !ok {
+		return
+	}
+
+	_, err := cfg.dbQueries.DeleteFollow(r.Context(), database.DeleteFollowParams{
+		FollowerID: userID,
+		FollowedID: targetID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetFollowers lists who follows a user, most recent first. Users the
+// viewer could not see elsewhere are left out.
+func (cfg *apiConfig) handleGetFollowers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := cfg.pathUserTarget(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := database.ListFollowersParams{
+		UserID:   userID,
+		ViewerID: cfg.viewerID(r),
+		Limit:    limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorUserID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	dbFollowers, err := cfg.dbQueries.ListFollowers(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	page := RelatedUsersPage{Users: []RelatedUser{}}
+	if len(dbFollowers) > int(limit) {
+		dbFollowers = dbFollowers[:limit]
+		last := dbFollowers[len(dbFollowers)-1]
+		page.NextCursor = pagination.EncodeCursor(pagination.Cursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.UserID,
+		})
+	}
+
+	for _, dbFollower := range dbFollowers {
+		page.Users = append(page.Users, RelatedUser{
+			UserID:    dbFollower.UserID,
+			Handle:    dbFollower.Handle,
+			CreatedAt: dbFollower.CreatedAt,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+// handleGetFollowing lists who a user follows, most recent first. Users the
+// viewer could not see elsewhere are left out.
+func (cfg *apiConfig) handleGetFollowing(w http.ResponseWriter, r *http.Request) {
+	userID, ok := cfg.pathUserTarget(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := database.ListFollowingParams{
+		UserID:   userID,
+		ViewerID: cfg.viewerID(r),
+		Limit:    limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorUserID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	dbFollowing, err := cfg.dbQueries.ListFollowing(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	page := RelatedUsersPage{Users: []RelatedUser{}}
+	if len(dbFollowing) > int(limit) {
+		dbFollowing = dbFollowing[:limit]
+		last := dbFollowing[len(dbFollowing)-1]
+		page.NextCursor = pagination.EncodeCursor(pagination.Cursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.UserID,
+		})
+	}
+
+	for _, dbFollowed := range dbFollowing {
+		page.Users = append(page.Users, RelatedUser{
+			UserID:    dbFollowed.UserID,
+			Handle:    dbFollowed.Handle,
+			CreatedAt: dbFollowed.CreatedAt,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+// handleGetTimeline lists the chirps of the accounts the caller follows and
+// the caller's own, newest first. Blocks, mutes, shadowbans and timeline mute
+// rules apply as they do to GET /api/chirps.
+func (cfg *apiConfig) handleGetTimeline(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := database.ListTimelineParams{
+		ViewerID: userID,
+		Limit:    limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	dbChirps, err := cfg.dbQueries.ListTimeline(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	viewerID := uuid.NullUUID{UUID: userID, Valid: true}
+	page, err := cfg.newChirpsPage(r.Context(), viewerID, dbChirps, limit, muteScopeTimeline)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
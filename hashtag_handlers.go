@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/entities"
+	"github.com/pedroomedicina/chirpy/internal/pagination"
+	"net/http"
+)
+
+// trendingMinUses keeps tags used only once or twice in the window from
+// trending on growth alone.
+const trendingMinUses = 3
+
+// saveChirpHashtags replaces the hashtags stored for a chirp with the ones
+// found in its body. Each use is dated to the chirp's creation, so editing a
+// chirp does not bring its hashtags back into the trending window.
+func saveChirpHashtags(ctx context.Context, q *database.Queries, dbChirp database.Chirp) error {
+	err := q.DeleteChirpHashtags(ctx, dbChirp.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range entities.Hashtags(dbChirp.Body) {
+		hashtag, err := q.UpsertHashtag(ctx, tag)
+		if err != nil {
+			return err
+		}
+
+		err = q.CreateChirpHashtag(ctx, database.CreateChirpHashtagParams{
+			ChirpID:   dbChirp.ID,
+			HashtagID: hashtag.ID,
+			CreatedAt: dbChirp.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cfg *apiConfig) handleGetHashtagChirps(w http.ResponseWriter, r *http.Request) {
+	tag := entities.NormalizeHashtag(r.PathValue("tag"))
+	if tag == "" {
+		respondWithError(w, http.StatusBadRequest, "Invalid hashtag")
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	viewerID := cfg.viewerID(r)
+	params := database.ListHashtagChirpsParams{
+		Tag:      tag,
+		ViewerID: viewerID,
+		Limit:    limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	dbChirps, err := cfg.dbQueries.ListHashtagChirps(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	page, err := cfg.newChirpsPage(r.Context(), viewerID, dbChirps, limit, muteScopeEverywhere)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+// handleGetTrendingHashtags ranks tags by how much their usage grew in the
+// latest trending window compared to the window before it.
+func (cfg *apiConfig) handleGetTrendingHashtags(w http.ResponseWriter, r *http.Request) {
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := cfg.dbQueries.GetTrendingHashtags(r.Context(), database.GetTrendingHashtagsParams{
+		WindowSeconds: int32(cfg.trendingWindow.Seconds()),
+		MinCount:      trendingMinUses,
+		Limit:         limit,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	trending := []TrendingHashtag{}
+	for _, row := range rows {
+		trending = append(trending, TrendingHashtag{
+			Tag:           row.Tag,
+			RecentCount:   row.RecentCount,
+			PreviousCount: row.PreviousCount,
+			Growth:        row.Growth,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, trending)
+}
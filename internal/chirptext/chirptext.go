@@ -0,0 +1,30 @@
+package chirptext
+
+import (
+	"github.com/pedroomedicina/chirpy/internal/entities"
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+)
+
+// URLWeight is the number of characters a link counts as, however long it is.
+const URLWeight = 23
+
+// Normalize puts text in the form chirps are stored and measured in.
+func Normalize(text string) string {
+	return norm.NFC.String(text)
+}
+
+// Length measures text the way users count characters: in grapheme clusters
+// after NFC normalization, so an emoji sequence or an accented letter counts
+// as one. Each link counts as URLWeight.
+func Length(text string) int {
+	runes := []rune(Normalize(text))
+	length := 0
+	offset := 0
+	for _, url := range entities.ExtractURLs(string(runes)) {
+		length += uniseg.GraphemeClusterCount(string(runes[offset:url.Start])) + URLWeight
+		offset = url.End
+	}
+
+	return length + uniseg.GraphemeClusterCount(string(runes[offset:]))
+}
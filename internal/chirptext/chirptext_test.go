@@ -0,0 +1,40 @@
+package chirptext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLengthCountsGraphemeClusters(t *testing.T) {
+	cases := map[string]int{
+		"hello":      5,
+		"こんにちは":      5,
+		"👍🏽":         1,
+		"👩‍👩‍👧‍👦":    1,
+		"🇯🇵🇧🇷":       2,
+		"cafe\u0301": 4,
+		"café":       4,
+		"":           0,
+	}
+
+	for text, expected := range cases {
+		if length := Length(text); length != expected {
+			t.Errorf("Expected length of %q to be %d, got %d", text, expected, length)
+		}
+	}
+}
+
+func TestLengthWeighsURLs(t *testing.T) {
+	short := Length("see https://go.dev")
+	long := Length("see https://example.com/" + strings.Repeat("a", 200))
+
+	if short != 4+URLWeight || long != 4+URLWeight {
+		t.Fatalf("Expected both lengths to be %d, got %d and %d", 4+URLWeight, short, long)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if Normalize("cafe\u0301") != "café" {
+		t.Fatalf("Expected decomposed text to be composed")
+	}
+}
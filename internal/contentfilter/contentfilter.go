@@ -0,0 +1,70 @@
+package contentfilter
+
+// Action is what a filter does with content it matches.
+type Action int
+
+const (
+	// Mask replaces the matched content with asterisks.
+	Mask Action = iota
+	// Reject refuses the chirp outright.
+	Reject
+	// Flag lets the chirp through unchanged but records why it matched.
+	Flag
+)
+
+const mask = "****"
+
+// Verdict is what a single filter decided about a chirp. Text is the chirp as
+// the filter left it, which is what the next filter in a pipeline sees.
+type Verdict struct {
+	Text     string
+	Rejected bool
+	Reason   string
+	Flags    []string
+}
+
+// ContentFilter inspects a chirp body and can rewrite, reject or flag it.
+type ContentFilter interface {
+	Name() string
+	Apply(text string) Verdict
+}
+
+// FlagResult records that a filter let a chirp through but wants it looked at.
+type FlagResult struct {
+	Filter string
+	Reason string
+}
+
+// Result is the outcome of running a chirp through a pipeline.
+type Result struct {
+	Text     string
+	Rejected bool
+	Reason   string
+	Flags    []FlagResult
+}
+
+// Pipeline runs filters in order, each one seeing the text as rewritten by
+// the ones before it. It stops at the first filter that rejects.
+type Pipeline []ContentFilter
+
+func (p Pipeline) Apply(text string) Result {
+	result := Result{Text: text}
+	for _, filter := range p {
+		verdict := filter.Apply(result.Text)
+		for _, reason := range verdict.Flags {
+			result.Flags = append(result.Flags, FlagResult{
+				Filter: filter.Name(),
+				Reason: reason,
+			})
+		}
+
+		if verdict.Rejected {
+			result.Rejected = true
+			result.Reason = verdict.Reason
+			return result
+		}
+		result.Text = verdict.Text
+	}
+
+	return result
+}
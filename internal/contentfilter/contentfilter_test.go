@@ -0,0 +1,158 @@
+package contentfilter
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWordFilterMasksAroundPunctuation(t *testing.T) {
+	filter := NewWordFilter(map[string]Action{"kerfuffle": Mask, "fornax": Mask})
+
+	cases := map[string]string{
+		"What a Kerfuffle!":         "What a ****!",
+		"fornax, again":             "****, again",
+		"(kerfuffle)/fornax":        "(****)/****",
+		"k3rfuffl3 and f0rn@x":      "**** and ****",
+		"k\u0435rfuffle":            "****",
+		"KÉRFUFFLE":                 "****",
+		"ｋｅｒｆｕｆｆｌｅ":                 "****",
+		"kerfuffles are fine":       "kerfuffles are fine",
+		"a fornaxian kerfufflement": "a fornaxian kerfufflement",
+	}
+
+	for text, expected := range cases {
+		verdict := filter.Apply(text)
+		if verdict.Rejected || verdict.Text != expected {
+			t.Errorf("Expected %q to become %q, got %+v", text, expected, verdict)
+		}
+	}
+}
+
+func TestWordFilterRejectsAndFlags(t *testing.T) {
+	filter := NewWordFilter(map[string]Action{"sharbert": Reject, "fornax": Flag})
+
+	verdict := filter.Apply("fornax sh4rbert")
+	if !verdict.Rejected {
+		t.Fatalf("Expected chirp to be rejected, got %+v", verdict)
+	}
+
+	verdict = filter.Apply("fornax!")
+	if verdict.Rejected || verdict.Text != "fornax!" || len(verdict.Flags) != 1 {
+		t.Fatalf("Expected chirp to be flagged unchanged, got %+v", verdict)
+	}
+}
+
+func TestRegexFilter(t *testing.T) {
+	filter := NewRegexFilter(
+		RegexRule{Pattern: regexp.MustCompile(`\d{3}-\d{4}`), Action: Mask},
+		RegexRule{Pattern: regexp.MustCompile(`(?i)free money`), Action: Flag, Reason: "spam phrase"},
+	)
+
+	verdict := filter.Apply("Free money! Call 555-1234")
+	expected := Verdict{Text: "Free money! Call ****", Flags: []string{"spam phrase"}}
+	if !reflect.DeepEqual(verdict, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, verdict)
+	}
+}
+
+func TestLoadRegexRules(t *testing.T) {
+	rules, err := LoadRegexRules(strings.NewReader(`[
+		{"pattern": "(?i)free money", "action": "flag", "reason": "spam phrase"},
+		{"pattern": "\\d{3}-\\d{4}", "action": "mask"}
+	]`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	verdict := NewRegexFilter(rules...).Apply("FREE MONEY at 555-1234")
+	if verdict.Text != "FREE MONEY at ****" || len(verdict.Flags) != 1 || verdict.Flags[0] != "spam phrase" {
+		t.Fatalf("Expected a masked and flagged verdict, got %+v", verdict)
+	}
+
+	_, err = LoadRegexRules(strings.NewReader(`[{"pattern": "x", "action": "delete"}]`))
+	if err == nil {
+		t.Fatal("Expected an unknown action to be an error")
+	}
+
+	_, err = LoadRegexRules(strings.NewReader(`[{"pattern": "(", "action": "flag"}]`))
+	if err == nil {
+		t.Fatal("Expected an invalid pattern to be an error")
+	}
+}
+
+func TestPipelineRunsInOrderAndStopsAtReject(t *testing.T) {
+	pipeline := Pipeline{
+		NewWordFilter(map[string]Action{"fornax": Mask}),
+		NewRegexFilter(RegexRule{Pattern: regexp.MustCompile(`\*\*\*\*`), Action: Flag, Reason: "masked"}),
+		NewRegexFilter(RegexRule{Pattern: regexp.MustCompile(`reject me`), Action: Reject, Reason: "no"}),
+	}
+
+	result := pipeline.Apply("fornax")
+	expected := Result{Text: "****", Flags: []FlagResult{{Filter: "regex", Reason: "masked"}}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, result)
+	}
+
+	result = pipeline.Apply("please reject me")
+	if !result.Rejected || result.Reason != "no" {
+		t.Fatalf("Expected chirp to be rejected, got %+v", result)
+	}
+}
+
+func TestWordFilterSetWords(t *testing.T) {
+	filter := NewWordFilter(map[string]Action{"fornax": Mask})
+	filter.SetWords(map[string]Action{"sharbert": Mask})
+
+	verdict := filter.Apply("fornax sharbert")
+	if verdict.Text != "fornax ****" {
+		t.Fatalf("Expected only the new word list to apply, got %+v", verdict)
+	}
+}
+
+func TestWordFilterSetWordsKeepsStricterAction(t *testing.T) {
+	// Run it a few times, since map iteration order would otherwise decide.
+	for i := 0; i < 10; i++ {
+		filter := NewWordFilter(map[string]Action{"kerfuffle": Mask, "k3rfuffle": Reject, "kerfuff1e": Flag})
+		verdict := filter.Apply("what a kerfuffle")
+		if !verdict.Rejected {
+			t.Fatalf("Expected the stricter action to win, got %+v", verdict)
+		}
+	}
+}
+
+func TestValidWord(t *testing.T) {
+	for word, expected := range map[string]bool{"fornax": true, "sh4rb3rt": true, "": false, "two words": false, "f-bomb": false} {
+		if ValidWord(word) != expected {
+			t.Errorf("Expected ValidWord(%q) to be %v", word, expected)
+		}
+	}
+}
+
+func TestPhraseMatchString(t *testing.T) {
+	phrase := NewPhrase("Spoiler Alert!")
+	if !reflect.DeepEqual(phrase, Phrase{"spoiier", "aiert"}) {
+		t.Fatalf("Unexpected phrase: %q", phrase)
+	}
+
+	cases := map[string]bool{
+		"SPOILER ALERT: he wins":  true,
+		"sp0iler, alert":          true,
+		"#spoiler alert":          true,
+		"spoiler-free alert":      false,
+		"alert spoiler":           false,
+		"no spoilers alert here":  false,
+		"the spoiler is an alert": false,
+	}
+
+	for text, expected := range cases {
+		if phrase.MatchString(text) != expected {
+			t.Errorf("Expected MatchString(%q) to be %v", text, expected)
+		}
+	}
+
+	if NewPhrase("...").MatchString("...") {
+		t.Error("Expected a phrase without words to match nothing")
+	}
+}
@@ -0,0 +1,47 @@
+package contentfilter
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+// lookalikes maps characters commonly swapped in to dodge filters onto the
+// letter they imitate: leetspeak digits and symbols, and Cyrillic and Greek
+// letters that render like Latin ones. 'l' shares 'i' with '1', '!' and '|'
+// since either reading is plausible.
+var lookalikes = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b', '9': 'g',
+	'@': 'a', '$': 's', '!': 'i', '|': 'i', 'l': 'i',
+	'а': 'a', 'в': 'b', 'е': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p',
+	'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'і': 'i', 'ј': 'j', 'ѕ': 's', 'ԁ': 'd',
+	'α': 'a', 'β': 'b', 'ε': 'e', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p',
+	'τ': 't', 'υ': 'u', 'χ': 'x',
+}
+
+// Fold reduces a word to the form filters compare on: compatibility
+// decomposed, lowercased, stripped of accents and with lookalike characters
+// replaced, so "K3rfüffle" and "ｋｅｒｆｕｆｆｌｅ" fold the same as
+// "kerfuffle".
+func Fold(word string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(word) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		r = unicode.ToLower(r)
+		if lookalike, ok := lookalikes[r]; ok {
+			r = lookalike
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// isFoldable reports whether r can be part of a word, including the symbols
+// that stand in for letters.
+func isFoldable(r rune) bool {
+	_, lookalike := lookalikes[r]
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || lookalike
+}
@@ -0,0 +1,86 @@
+package contentfilter
+
+// Phrase is a run of one or more whole words, matched the same way WordFilter
+// matches single words.
+type Phrase []string
+
+// NewPhrase folds the words in text into a phrase. The phrase is empty when
+// text has no words.
+func NewPhrase(text string) Phrase {
+	var phrase Phrase
+	for _, candidates := range words(text) {
+		phrase = append(phrase, candidates[len(candidates)-1])
+	}
+
+	return phrase
+}
+
+// MatchString reports whether text contains the phrase.
+func (p Phrase) MatchString(text string) bool {
+	if len(p) == 0 {
+		return false
+	}
+
+	textWords := words(text)
+	for i := 0; i+len(p) <= len(textWords); i++ {
+		matched := true
+		for j, word := range p {
+			if !contains(textWords[i+j], word) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// words splits text into words and returns the folded forms each word can be
+// matched by: as written and, when it has symbols at either end, without
+// them. The last form is always the one without surrounding symbols.
+func words(text string) [][]string {
+	runes := []rune(text)
+	var result [][]string
+
+	for i := 0; i < len(runes); {
+		if !isFoldable(runes[i]) {
+			i++
+			continue
+		}
+
+		end := i
+		for end < len(runes) && isFoldable(runes[end]) {
+			end++
+		}
+
+		candidates := []string{Fold(string(runes[i:end]))}
+		trimmedStart, trimmedEnd := i, end
+		for trimmedStart < trimmedEnd && !isLetterOrDigit(runes[trimmedStart]) {
+			trimmedStart++
+		}
+		for trimmedEnd > trimmedStart && !isLetterOrDigit(runes[trimmedEnd-1]) {
+			trimmedEnd--
+		}
+		if trimmedStart < trimmedEnd && (trimmedStart != i || trimmedEnd != end) {
+			candidates = append(candidates, Fold(string(runes[trimmedStart:trimmedEnd])))
+		}
+
+		result = append(result, candidates)
+		i = end
+	}
+
+	return result
+}
+
+func contains(candidates []string, word string) bool {
+	for _, candidate := range candidates {
+		if candidate == word {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,95 @@
+package contentfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// RegexRule applies Action wherever Pattern matches. Reason is reported when
+// the rule rejects or flags a chirp.
+type RegexRule struct {
+	Pattern *regexp.Regexp
+	Action  Action
+	Reason  string
+}
+
+var regexActions = map[string]Action{
+	"mask":   Mask,
+	"reject": Reject,
+	"flag":   Flag,
+}
+
+// LoadRegexRules reads rules from a JSON array such as
+//
+//	[{"pattern": "(?i)free money", "action": "flag", "reason": "spam phrase"}]
+//
+// where action is "mask", "reject" or "flag".
+func LoadRegexRules(r io.Reader) ([]RegexRule, error) {
+	var configs []struct {
+		Pattern string `json:"pattern"`
+		Action  string `json:"action"`
+		Reason  string `json:"reason"`
+	}
+	err := json.NewDecoder(r).Decode(&configs)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]RegexRule, 0, len(configs))
+	for i, config := range configs {
+		pattern, err := regexp.Compile(config.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+
+		action, ok := regexActions[config.Action]
+		if !ok {
+			return nil, fmt.Errorf("rule %d: unknown action %q", i, config.Action)
+		}
+
+		rules = append(rules, RegexRule{
+			Pattern: pattern,
+			Action:  action,
+			Reason:  config.Reason,
+		})
+	}
+
+	return rules, nil
+}
+
+// RegexFilter applies rules in order.
+type RegexFilter struct {
+	rules []RegexRule
+}
+
+func NewRegexFilter(rules ...RegexRule) *RegexFilter {
+	return &RegexFilter{rules: rules}
+}
+
+func (f *RegexFilter) Name() string {
+	return "regex"
+}
+
+func (f *RegexFilter) Apply(text string) Verdict {
+	verdict := Verdict{Text: text}
+	for _, rule := range f.rules {
+		if !rule.Pattern.MatchString(verdict.Text) {
+			continue
+		}
+
+		switch rule.Action {
+		case Reject:
+			verdict.Rejected = true
+			verdict.Reason = rule.Reason
+			return verdict
+		case Flag:
+			verdict.Flags = append(verdict.Flags, rule.Reason)
+		default:
+			verdict.Text = rule.Pattern.ReplaceAllLiteralString(verdict.Text, mask)
+		}
+	}
+
+	return verdict
+}
@@ -0,0 +1,151 @@
+package contentfilter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// WordFilter matches whole words against a list, regardless of case, accents,
+// lookalike characters or surrounding punctuation. The list can be replaced
+// while the filter is in use.
+type WordFilter struct {
+	mu    sync.RWMutex
+	words map[string]Action
+}
+
+// NewWordFilter builds a filter that applies the given action to each word.
+func NewWordFilter(words map[string]Action) *WordFilter {
+	f := &WordFilter{}
+	f.SetWords(words)
+	return f
+}
+
+// SetWords replaces the filter's word list. Words that fold the same, such as
+// "kerfuffle" and "k3rfuffle", get the stricter of their actions.
+func (f *WordFilter) SetWords(words map[string]Action) {
+	folded := make(map[string]Action, len(words))
+	for word, action := range words {
+		key := Fold(word)
+		if existing, ok := folded[key]; ok && strictness(existing) >= strictness(action) {
+			continue
+		}
+		folded[key] = action
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.words = folded
+}
+
+// strictness orders actions from flagging, which changes nothing, through
+// masking to rejecting.
+func strictness(action Action) int {
+	switch action {
+	case Reject:
+		return 2
+	case Mask:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ValidWord reports whether word is a single word the filter can match.
+func ValidWord(word string) bool {
+	if word == "" {
+		return false
+	}
+
+	for _, r := range word {
+		if !isFoldable(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *WordFilter) Name() string {
+	return "words"
+}
+
+func (f *WordFilter) Apply(text string) Verdict {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	runes := []rune(text)
+	var b strings.Builder
+	verdict := Verdict{}
+
+	for i := 0; i < len(runes); {
+		if !isFoldable(runes[i]) {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		end := i
+		for end < len(runes) && isFoldable(runes[end]) {
+			end++
+		}
+
+		start, stop, action, ok := f.match(runes, i, end)
+		if !ok {
+			b.WriteString(string(runes[i:end]))
+			i = end
+			continue
+		}
+
+		word := string(runes[start:stop])
+		switch action {
+		case Reject:
+			return Verdict{
+				Text:     text,
+				Rejected: true,
+				Reason:   "Chirp contains a banned word",
+				Flags:    verdict.Flags,
+			}
+		case Flag:
+			verdict.Flags = append(verdict.Flags, fmt.Sprintf("contains %q", word))
+			b.WriteString(string(runes[i:end]))
+		default:
+			b.WriteString(string(runes[i:start]))
+			b.WriteString(mask)
+			b.WriteString(string(runes[stop:end]))
+		}
+		i = end
+	}
+
+	verdict.Text = b.String()
+	return verdict
+}
+
+// match looks up the word in runes[start:end]. Symbols at either end may be
+// punctuation rather than stand-ins for letters, as in "fornax!", so the word
+// is tried again without them.
+func (f *WordFilter) match(runes []rune, start, end int) (int, int, Action, bool) {
+	if action, ok := f.words[Fold(string(runes[start:end]))]; ok {
+		return start, end, action, true
+	}
+
+	trimmedStart, trimmedEnd := start, end
+	for trimmedStart < trimmedEnd && !isLetterOrDigit(runes[trimmedStart]) {
+		trimmedStart++
+	}
+	for trimmedEnd > trimmedStart && !isLetterOrDigit(runes[trimmedEnd-1]) {
+		trimmedEnd--
+	}
+
+	if trimmedStart == trimmedEnd || (trimmedStart == start && trimmedEnd == end) {
+		return 0, 0, 0, false
+	}
+
+	action, ok := f.words[Fold(string(runes[trimmedStart:trimmedEnd]))]
+	return trimmedStart, trimmedEnd, action, ok
+}
+
+func isLetterOrDigit(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -7,29 +7,82 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const countChirpsSince = `-- name: CountChirpsSince :one
+SELECT COUNT(*)
+FROM chirps
+WHERE user_id = $1
+  AND created_at > NOW() - $2::integer * INTERVAL '1 second'
+  AND id IS DISTINCT FROM $3::uuid
+`
+
+type CountChirpsSinceParams struct {
+	UserID        uuid.UUID
+	WindowSeconds int32
+	ExcludeID     uuid.NullUUID
+}
+
+func (q *Queries) CountChirpsSince(ctx context.Context, arg CountChirpsSinceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countChirpsSince, arg.UserID, arg.WindowSeconds, arg.ExcludeID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countRecentChirps = `-- name: CountRecentChirps :one
+SELECT COUNT(*)
+FROM chirps
+WHERE user_id = $1
+  AND created_at > NOW() - INTERVAL '1 day'
+`
+
+func (q *Queries) CountRecentChirps(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRecentChirps, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createChirp = `-- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, parent_chirp_id, quoted_chirp_id, publish_at, expires_at)
 VALUES (
     gen_random_uuid(),
     NOW(),
     NOW(),
     $1,
-    $2
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
 )
-RETURNING id, created_at, updated_at, body, user_id
+RETURNING id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
 `
 
 type CreateChirpParams struct {
-	Body   string
-	UserID uuid.UUID
+	Body          string
+	UserID        uuid.UUID
+	ParentChirpID uuid.NullUUID
+	QuotedChirpID uuid.NullUUID
+	PublishAt     sql.NullTime
+	ExpiresAt     sql.NullTime
 }
 
 func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, createChirp, arg.Body, arg.UserID)
+	row := q.db.QueryRowContext(ctx, createChirp,
+		arg.Body,
+		arg.UserID,
+		arg.ParentChirpID,
+		arg.QuotedChirpID,
+		arg.PublishAt,
+		arg.ExpiresAt,
+	)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -37,6 +90,14 @@ func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.SearchVector,
+		&i.ParentChirpID,
+		&i.TombstonedAt,
+		&i.QuotedChirpID,
+		&i.PublishAt,
+		&i.DeletedAt,
+		&i.ExpiresAt,
+		&i.HiddenAt,
 	)
 	return i, err
 }
@@ -52,7 +113,7 @@ func (q *Queries) DeleteChirp(ctx context.Context, id uuid.UUID) error {
 }
 
 const getAllChirps = `-- name: GetAllChirps :many
-SELECT id, created_at, updated_at, body, user_id
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
 FROM chirps
 `
 
@@ -71,6 +132,14 @@ func (q *Queries) GetAllChirps(ctx context.Context) ([]Chirp, error) {
 			&i.UpdatedAt,
 			&i.Body,
 			&i.UserID,
+			&i.SearchVector,
+			&i.ParentChirpID,
+			&i.TombstonedAt,
+			&i.QuotedChirpID,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.ExpiresAt,
+			&i.HiddenAt,
 		); err != nil {
 			return nil, err
 		}
@@ -86,7 +155,7 @@ func (q *Queries) GetAllChirps(ctx context.Context) ([]Chirp, error) {
 }
 
 const getChirpByID = `-- name: GetChirpByID :one
-SELECT id, created_at, updated_at, body, user_id
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
 FROM chirps
 WHERE id = $1
 `
@@ -100,12 +169,91 @@ func (q *Queries) GetChirpByID(ctx context.Context, id uuid.UUID) (Chirp, error)
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.SearchVector,
+		&i.ParentChirpID,
+		&i.TombstonedAt,
+		&i.QuotedChirpID,
+		&i.PublishAt,
+		&i.DeletedAt,
+		&i.ExpiresAt,
+		&i.HiddenAt,
+	)
+	return i, err
+}
+
+const getChirpByIDForUpdate = `-- name: GetChirpByIDForUpdate :one
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
+FROM chirps
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetChirpByIDForUpdate(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirpByIDForUpdate, id)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.ParentChirpID,
+		&i.TombstonedAt,
+		&i.QuotedChirpID,
+		&i.PublishAt,
+		&i.DeletedAt,
+		&i.ExpiresAt,
+		&i.HiddenAt,
 	)
 	return i, err
 }
 
+const getChirpsByIDs = `-- name: GetChirpsByIDs :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
+FROM chirps
+WHERE id = ANY($1::uuid[])
+`
+
+func (q *Queries) GetChirpsByIDs(ctx context.Context, ids []uuid.UUID) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.ParentChirpID,
+			&i.TombstonedAt,
+			&i.QuotedChirpID,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.ExpiresAt,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getChirpsByUserId = `-- name: GetChirpsByUserId :many
-SELECT id, created_at, updated_at, body, user_id
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
 FROM chirps
 WHERE user_id = $1
 `
@@ -125,6 +273,337 @@ func (q *Queries) GetChirpsByUserId(ctx context.Context, userID uuid.UUID) ([]Ch
 			&i.UpdatedAt,
 			&i.Body,
 			&i.UserID,
+			&i.SearchVector,
+			&i.ParentChirpID,
+			&i.TombstonedAt,
+			&i.QuotedChirpID,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.ExpiresAt,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const hideChirp = `-- name: HideChirp :exec
+UPDATE chirps
+SET hidden_at = COALESCE(hidden_at, NOW())
+WHERE id = $1
+`
+
+func (q *Queries) HideChirp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, hideChirp, id)
+	return err
+}
+
+const listAutoDeletedChirps = `-- name: ListAutoDeletedChirps :many
+SELECT chirps.id
+FROM users
+INNER JOIN chirps ON chirps.user_id = users.id
+WHERE users.auto_delete_after_days IS NOT NULL
+  AND chirps.created_at < NOW() - users.auto_delete_after_days * INTERVAL '1 day'
+  AND chirps.publish_at IS NULL
+  AND chirps.tombstoned_at IS NULL
+  AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+ORDER BY chirps.created_at ASC
+LIMIT $1
+FOR UPDATE OF chirps SKIP LOCKED
+`
+
+func (q *Queries) ListAutoDeletedChirps(ctx context.Context, limit int32) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, listAutoDeletedChirps, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChirpsAsc = `-- name: ListChirpsAsc :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
+FROM chirps
+WHERE tombstoned_at IS NULL
+  AND publish_at IS NULL
+  AND deleted_at IS NULL
+  AND hidden_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND ($1::uuid IS NULL OR user_id = $1)
+  AND ($2::timestamp IS NULL
+    OR (created_at, id) > ($2::timestamp, $3::uuid))
+  AND user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = $4::uuid)
+  AND user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM $4::uuid))
+ORDER BY created_at ASC, id ASC
+LIMIT $5
+`
+
+type ListChirpsAscParams struct {
+	AuthorID        uuid.NullUUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	ViewerID        uuid.NullUUID
+	Limit           int32
+}
+
+func (q *Queries) ListChirpsAsc(ctx context.Context, arg ListChirpsAscParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsAsc,
+		arg.AuthorID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.ViewerID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.ParentChirpID,
+			&i.TombstonedAt,
+			&i.QuotedChirpID,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.ExpiresAt,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChirpsDesc = `-- name: ListChirpsDesc :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
+FROM chirps
+WHERE tombstoned_at IS NULL
+  AND publish_at IS NULL
+  AND deleted_at IS NULL
+  AND hidden_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND ($1::uuid IS NULL OR user_id = $1)
+  AND ($2::timestamp IS NULL
+    OR (created_at, id) < ($2::timestamp, $3::uuid))
+  AND user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = $4::uuid)
+  AND user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM $4::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT $5
+`
+
+type ListChirpsDescParams struct {
+	AuthorID        uuid.NullUUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	ViewerID        uuid.NullUUID
+	Limit           int32
+}
+
+func (q *Queries) ListChirpsDesc(ctx context.Context, arg ListChirpsDescParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsDesc,
+		arg.AuthorID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.ViewerID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.ParentChirpID,
+			&i.TombstonedAt,
+			&i.QuotedChirpID,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.ExpiresAt,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpiredChirps = `-- name: ListExpiredChirps :many
+SELECT id
+FROM chirps
+WHERE expires_at <= NOW()
+  AND tombstoned_at IS NULL
+ORDER BY expires_at ASC
+LIMIT $1
+FOR UPDATE SKIP LOCKED
+`
+
+func (q *Queries) ListExpiredChirps(ctx context.Context, limit int32) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, listExpiredChirps, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPurgeableChirps = `-- name: ListPurgeableChirps :many
+SELECT id
+FROM chirps
+WHERE deleted_at < NOW() - $1::integer * INTERVAL '1 second'
+  AND tombstoned_at IS NULL
+ORDER BY deleted_at ASC
+LIMIT $2
+FOR UPDATE SKIP LOCKED
+`
+
+type ListPurgeableChirpsParams struct {
+	RetentionSeconds int32
+	Limit            int32
+}
+
+func (q *Queries) ListPurgeableChirps(ctx context.Context, arg ListPurgeableChirpsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, listPurgeableChirps, arg.RetentionSeconds, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listQuoteChirps = `-- name: ListQuoteChirps :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
+FROM chirps
+WHERE quoted_chirp_id = $1
+  AND tombstoned_at IS NULL
+  AND publish_at IS NULL
+  AND deleted_at IS NULL
+  AND hidden_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND ($2::timestamp IS NULL
+    OR (created_at, id) < ($2::timestamp, $3::uuid))
+  AND user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = $4::uuid)
+  AND user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM $4::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT $5
+`
+
+type ListQuoteChirpsParams struct {
+	QuotedChirpID   uuid.NullUUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	ViewerID        uuid.NullUUID
+	Limit           int32
+}
+
+func (q *Queries) ListQuoteChirps(ctx context.Context, arg ListQuoteChirpsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listQuoteChirps,
+		arg.QuotedChirpID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.ViewerID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.ParentChirpID,
+			&i.TombstonedAt,
+			&i.QuotedChirpID,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.ExpiresAt,
+			&i.HiddenAt,
 		); err != nil {
 			return nil, err
 		}
@@ -138,3 +617,652 @@ func (q *Queries) GetChirpsByUserId(ctx context.Context, userID uuid.UUID) ([]Ch
 	}
 	return items, nil
 }
+
+const listRecentChirpBodies = `-- name: ListRecentChirpBodies :many
+SELECT body
+FROM chirps
+WHERE user_id = $1
+  AND created_at > NOW() - $2::integer * INTERVAL '1 second'
+  AND tombstoned_at IS NULL
+  AND id IS DISTINCT FROM $3::uuid
+ORDER BY created_at DESC
+LIMIT $4
+`
+
+type ListRecentChirpBodiesParams struct {
+	UserID        uuid.UUID
+	WindowSeconds int32
+	ExcludeID     uuid.NullUUID
+	Limit         int32
+}
+
+func (q *Queries) ListRecentChirpBodies(ctx context.Context, arg ListRecentChirpBodiesParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listRecentChirpBodies,
+		arg.UserID,
+		arg.WindowSeconds,
+		arg.ExcludeID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var body string
+		if err := rows.Scan(&body); err != nil {
+			return nil, err
+		}
+		items = append(items, body)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScheduledChirps = `-- name: ListScheduledChirps :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
+FROM chirps
+WHERE user_id = $1
+  AND publish_at IS NOT NULL
+  AND ($2::timestamp IS NULL
+    OR (publish_at, id) > ($2::timestamp, $3::uuid))
+ORDER BY publish_at ASC, id ASC
+LIMIT $4
+`
+
+type ListScheduledChirpsParams struct {
+	UserID          uuid.UUID
+	CursorPublishAt sql.NullTime
+	CursorID        uuid.NullUUID
+	Limit           int32
+}
+
+func (q *Queries) ListScheduledChirps(ctx context.Context, arg ListScheduledChirpsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listScheduledChirps,
+		arg.UserID,
+		arg.CursorPublishAt,
+		arg.CursorID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.ParentChirpID,
+			&i.TombstonedAt,
+			&i.QuotedChirpID,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.ExpiresAt,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTimeline = `-- name: ListTimeline :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
+FROM chirps
+WHERE user_id IN (
+    SELECT followed_id FROM follows WHERE follower_id = $1
+    UNION ALL
+    SELECT $1::uuid
+)
+  AND tombstoned_at IS NULL
+  AND publish_at IS NULL
+  AND deleted_at IS NULL
+  AND hidden_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND ($2::timestamp IS NULL
+    OR (created_at, id) < ($2::timestamp, $3::uuid))
+  AND user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = $1)
+  AND user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id <> $1))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
+`
+
+type ListTimelineParams struct {
+	ViewerID        uuid.UUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	Limit           int32
+}
+
+func (q *Queries) ListTimeline(ctx context.Context, arg ListTimelineParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listTimeline,
+		arg.ViewerID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.ParentChirpID,
+			&i.TombstonedAt,
+			&i.QuotedChirpID,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.ExpiresAt,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrashedChirps = `-- name: ListTrashedChirps :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
+FROM chirps
+WHERE user_id = $1
+  AND deleted_at IS NOT NULL
+  AND tombstoned_at IS NULL
+  AND hidden_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND ($2::timestamp IS NULL
+    OR (deleted_at, id) < ($2::timestamp, $3::uuid))
+ORDER BY deleted_at DESC, id DESC
+LIMIT $4
+`
+
+type ListTrashedChirpsParams struct {
+	UserID          uuid.UUID
+	CursorDeletedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	Limit           int32
+}
+
+func (q *Queries) ListTrashedChirps(ctx context.Context, arg ListTrashedChirpsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashedChirps,
+		arg.UserID,
+		arg.CursorDeletedAt,
+		arg.CursorID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.ParentChirpID,
+			&i.TombstonedAt,
+			&i.QuotedChirpID,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.ExpiresAt,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const publishDueChirps = `-- name: PublishDueChirps :many
+UPDATE chirps
+SET publish_at = NULL, created_at = NOW(), updated_at = NOW()
+WHERE id IN (
+    SELECT id
+    FROM chirps AS due
+    WHERE due.publish_at <= NOW()
+      AND due.deleted_at IS NULL
+      AND (due.expires_at IS NULL OR due.expires_at > NOW())
+    ORDER BY due.publish_at ASC
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
+`
+
+func (q *Queries) PublishDueChirps(ctx context.Context, limit int32) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, publishDueChirps, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.ParentChirpID,
+			&i.TombstonedAt,
+			&i.QuotedChirpID,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.ExpiresAt,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rescheduleChirp = `-- name: RescheduleChirp :one
+UPDATE chirps
+SET publish_at = $2, updated_at = NOW()
+WHERE id = $1
+  AND publish_at IS NOT NULL
+RETURNING id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
+`
+
+type RescheduleChirpParams struct {
+	ID        uuid.UUID
+	PublishAt sql.NullTime
+}
+
+func (q *Queries) RescheduleChirp(ctx context.Context, arg RescheduleChirpParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, rescheduleChirp, arg.ID, arg.PublishAt)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.ParentChirpID,
+		&i.TombstonedAt,
+		&i.QuotedChirpID,
+		&i.PublishAt,
+		&i.DeletedAt,
+		&i.ExpiresAt,
+		&i.HiddenAt,
+	)
+	return i, err
+}
+
+const restoreChirp = `-- name: RestoreChirp :one
+UPDATE chirps
+SET deleted_at = NULL
+WHERE id = $1
+  AND deleted_at IS NOT NULL
+  AND tombstoned_at IS NULL
+RETURNING id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
+`
+
+func (q *Queries) RestoreChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, restoreChirp, id)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.ParentChirpID,
+		&i.TombstonedAt,
+		&i.QuotedChirpID,
+		&i.PublishAt,
+		&i.DeletedAt,
+		&i.ExpiresAt,
+		&i.HiddenAt,
+	)
+	return i, err
+}
+
+const searchChirpsAsc = `-- name: SearchChirpsAsc :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
+FROM chirps
+WHERE search_vector @@ websearch_to_tsquery('english', $1)
+  AND tombstoned_at IS NULL
+  AND publish_at IS NULL
+  AND deleted_at IS NULL
+  AND hidden_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND ($2::uuid IS NULL OR user_id = $2)
+  AND ($3::timestamp IS NULL
+    OR (created_at, id) > ($3::timestamp, $4::uuid))
+  AND user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = $5::uuid)
+  AND user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM $5::uuid))
+ORDER BY created_at ASC, id ASC
+LIMIT $6
+`
+
+type SearchChirpsAscParams struct {
+	Query           string
+	AuthorID        uuid.NullUUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	ViewerID        uuid.NullUUID
+	Limit           int32
+}
+
+func (q *Queries) SearchChirpsAsc(ctx context.Context, arg SearchChirpsAscParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, searchChirpsAsc,
+		arg.Query,
+		arg.AuthorID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.ViewerID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.ParentChirpID,
+			&i.TombstonedAt,
+			&i.QuotedChirpID,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.ExpiresAt,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchChirpsByRank = `-- name: SearchChirpsByRank :many
+SELECT id, created_at, updated_at, body, user_id, parent_chirp_id, quoted_chirp_id, expires_at, rank
+FROM (
+    SELECT id, created_at, updated_at, body, user_id, parent_chirp_id, quoted_chirp_id, expires_at,
+        ts_rank(search_vector, websearch_to_tsquery('english', $1)) AS rank
+    FROM chirps
+    WHERE search_vector @@ websearch_to_tsquery('english', $1)
+      AND tombstoned_at IS NULL
+      AND publish_at IS NULL
+      AND deleted_at IS NULL
+      AND hidden_at IS NULL
+      AND (expires_at IS NULL OR expires_at > NOW())
+      AND ($2::uuid IS NULL OR user_id = $2)
+) AS ranked
+WHERE ($3::real IS NULL
+    OR (rank, created_at, id) < ($3::real, $4::timestamp, $5::uuid))
+  AND user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = $6::uuid)
+  AND user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM $6::uuid))
+ORDER BY rank DESC, created_at DESC, id DESC
+LIMIT $7
+`
+
+type SearchChirpsByRankParams struct {
+	Query           string
+	AuthorID        uuid.NullUUID
+	CursorRank      sql.NullFloat64
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	ViewerID        uuid.NullUUID
+	Limit           int32
+}
+
+type SearchChirpsByRankRow struct {
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Body          string
+	UserID        uuid.UUID
+	ParentChirpID uuid.NullUUID
+	QuotedChirpID uuid.NullUUID
+	ExpiresAt     sql.NullTime
+	Rank          float32
+}
+
+func (q *Queries) SearchChirpsByRank(ctx context.Context, arg SearchChirpsByRankParams) ([]SearchChirpsByRankRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchChirpsByRank,
+		arg.Query,
+		arg.AuthorID,
+		arg.CursorRank,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.ViewerID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchChirpsByRankRow
+	for rows.Next() {
+		var i SearchChirpsByRankRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ParentChirpID,
+			&i.QuotedChirpID,
+			&i.ExpiresAt,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchChirpsDesc = `-- name: SearchChirpsDesc :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
+FROM chirps
+WHERE search_vector @@ websearch_to_tsquery('english', $1)
+  AND tombstoned_at IS NULL
+  AND publish_at IS NULL
+  AND deleted_at IS NULL
+  AND hidden_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND ($2::uuid IS NULL OR user_id = $2)
+  AND ($3::timestamp IS NULL
+    OR (created_at, id) < ($3::timestamp, $4::uuid))
+  AND user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = $5::uuid)
+  AND user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM $5::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT $6
+`
+
+type SearchChirpsDescParams struct {
+	Query           string
+	AuthorID        uuid.NullUUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	ViewerID        uuid.NullUUID
+	Limit           int32
+}
+
+func (q *Queries) SearchChirpsDesc(ctx context.Context, arg SearchChirpsDescParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, searchChirpsDesc,
+		arg.Query,
+		arg.AuthorID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.ViewerID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.ParentChirpID,
+			&i.TombstonedAt,
+			&i.QuotedChirpID,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.ExpiresAt,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const softDeleteChirp = `-- name: SoftDeleteChirp :exec
+UPDATE chirps
+SET deleted_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) SoftDeleteChirp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, softDeleteChirp, id)
+	return err
+}
+
+const tombstoneChirp = `-- name: TombstoneChirp :exec
+UPDATE chirps
+SET body = '', tombstoned_at = NOW(), updated_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) TombstoneChirp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, tombstoneChirp, id)
+	return err
+}
+
+const unhideChirp = `-- name: UnhideChirp :exec
+UPDATE chirps
+SET hidden_at = NULL
+WHERE id = $1
+`
+
+func (q *Queries) UnhideChirp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, unhideChirp, id)
+	return err
+}
+
+const updateChirpBody = `-- name: UpdateChirpBody :one
+UPDATE chirps
+SET body = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, body, user_id, search_vector, parent_chirp_id, tombstoned_at, quoted_chirp_id, publish_at, deleted_at, expires_at, hidden_at
+`
+
+type UpdateChirpBodyParams struct {
+	ID   uuid.UUID
+	Body string
+}
+
+func (q *Queries) UpdateChirpBody(ctx context.Context, arg UpdateChirpBodyParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, updateChirpBody, arg.ID, arg.Body)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.ParentChirpID,
+		&i.TombstonedAt,
+		&i.QuotedChirpID,
+		&i.PublishAt,
+		&i.DeletedAt,
+		&i.ExpiresAt,
+		&i.HiddenAt,
+	)
+	return i, err
+}
\ No newline at end of file
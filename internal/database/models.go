@@ -6,17 +6,143 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-type Chirp struct {
+type Appeal struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UserID       uuid.UUID
+	SuspendedAt  time.Time
+	Message      string
+	Status       string
+	DecidedAt    sql.NullTime
+	DecisionNote string
+}
+
+type AuditLog struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	ActorType  string
+	ActorID    uuid.NullUUID
+	Action     string
+	TargetType string
+	TargetID   uuid.NullUUID
+	RequestID  string
+	Ip         string
+	Diff       json.RawMessage
+}
+
+type BannedWord struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Word      string
+	Severity  string
+}
+
+type Chirp struct {
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Body          string
+	UserID        uuid.UUID
+	SearchVector  interface{}
+	ParentChirpID uuid.NullUUID
+	TombstonedAt  sql.NullTime
+	QuotedChirpID uuid.NullUUID
+	PublishAt     sql.NullTime
+	DeletedAt     sql.NullTime
+	ExpiresAt     sql.NullTime
+	HiddenAt      sql.NullTime
+}
+
+type ChirpHashtag struct {
+	ChirpID   uuid.UUID
+	HashtagID uuid.UUID
+	CreatedAt time.Time
+}
+
+type ChirpLike struct {
+	UserID    uuid.UUID
+	ChirpID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type ChirpMention struct {
+	ChirpID   uuid.UUID
+	UserID    uuid.UUID
+	CreatedAt time.Time
+	Handle    string
+}
+
+type ChirpRevision struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	ChirpID   uuid.UUID
 	Body      string
+}
+
+type Follow struct {
+	FollowerID uuid.UUID
+	FollowedID uuid.UUID
+	CreatedAt  time.Time
+}
+
+type HandleRedirect struct {
+	Handle    string
+	UserID    uuid.UUID
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+type Hashtag struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	Tag       string
+}
+
+type MediaAttachment struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UserID       uuid.UUID
+	ChirpID      uuid.NullUUID
+	Position     int32
+	ContentType  string
+	StorageKey   string
+	ThumbnailKey string
+	Width        int32
+	Height       int32
+	AltText      string
+}
+
+type ModerationAction struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	ChirpID     uuid.NullUUID
+	UserID      uuid.NullUUID
+	Action      string
+	ReportCount int32
+	Note        string
+}
+
+type MuteRule struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
 	UserID    uuid.UUID
+	Kind      string
+	Value     string
+	Scope     string
+	ExpiresAt sql.NullTime
+}
+
+type Rechirp struct {
+	UserID    uuid.UUID
+	ChirpID   uuid.UUID
+	CreatedAt time.Time
 }
 
 type RefreshToken struct {
@@ -28,11 +154,44 @@ type RefreshToken struct {
 	RevokedAt sql.NullTime
 }
 
+type Report struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	ChirpID    uuid.UUID
+	ReporterID uuid.NullUUID
+	Reason     string
+	Details    string
+	ResolvedAt sql.NullTime
+	ResolvedBy uuid.NullUUID
+}
+
 type User struct {
-	ID             uuid.UUID
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
-	Email          string
-	HashedPassword string
-	IsChirpyRed    bool
+	ID                  uuid.UUID
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	Email               string
+	HashedPassword      string
+	IsChirpyRed         bool
+	AutoDeleteAfterDays sql.NullInt32
+	Status              string
+	StatusChangedAt     sql.NullTime
+	Handle              string
+	HandleChangedAt     sql.NullTime
+}
+
+type UserBlock struct {
+	BlockerID uuid.UUID
+	BlockedID uuid.UUID
+	CreatedAt time.Time
+}
+
+type UserMute struct {
+	MuterID   uuid.UUID
+	MutedID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type ViewerHiddenAuthor struct {
+	ViewerID uuid.UUID
+	AuthorID uuid.UUID
 }
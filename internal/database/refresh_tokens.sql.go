@@ -52,6 +52,7 @@ WHERE
     refresh_tokens.token = $1
   AND refresh_tokens.expires_at > NOW()
   AND refresh_tokens.revoked_at IS NULL
+  AND users.status <> 'suspended'
 `
 
 type GetUserFromRefreshTokenRow struct {
@@ -85,3 +86,16 @@ func (q *Queries) RevokeRefreshToken(ctx context.Context, token string) error {
 	_, err := q.db.ExecContext(ctx, revokeRefreshToken, token)
 	return err
 }
+
+const revokeUserRefreshTokens = `-- name: RevokeUserRefreshTokens :exec
+UPDATE refresh_tokens
+SET
+    revoked_at = NOW(),
+    updated_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, revokeUserRefreshTokens, userID)
+	return err
+}
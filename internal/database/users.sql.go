@@ -7,30 +7,34 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, email, hashed_password)
+INSERT INTO users (id, created_at, updated_at, email, hashed_password, handle)
 VALUES (
            gen_random_uuid(),
             NOW(),
             NOW(),
             $1,
-            $2
+            $2,
+            $3
        )
-RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red
+RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, auto_delete_after_days, status, status_changed_at, handle, handle_changed_at
 `
 
 type CreateUserParams struct {
 	Email          string
 	HashedPassword string
+	Handle         string
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, createUser, arg.Email, arg.HashedPassword)
+	row := q.db.QueryRowContext(ctx, createUser, arg.Email, arg.HashedPassword, arg.Handle)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -39,21 +43,29 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.AutoDeleteAfterDays,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.Handle,
+		&i.HandleChangedAt,
 	)
 	return i, err
 }
 
-const deleteAllUsers = `-- name: DeleteAllUsers :exec
+const deleteAllUsers = `-- name: DeleteAllUsers :execrows
 DELETE FROM users
 `
 
-func (q *Queries) DeleteAllUsers(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, deleteAllUsers)
-	return err
+func (q *Queries) DeleteAllUsers(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAllUsers)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, auto_delete_after_days, status, status_changed_at, handle, handle_changed_at
 FROM users
 WHERE email = $1
 `
@@ -68,15 +80,166 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.AutoDeleteAfterDays,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.Handle,
+		&i.HandleChangedAt,
+	)
+	return i, err
+}
+
+const getUserByHandle = `-- name: GetUserByHandle :one
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, auto_delete_after_days, status, status_changed_at, handle, handle_changed_at
+FROM users
+WHERE lower(handle) = lower($1)
+`
+
+func (q *Queries) GetUserByHandle(ctx context.Context, lower string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByHandle, lower)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.IsChirpyRed,
+		&i.AutoDeleteAfterDays,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.Handle,
+		&i.HandleChangedAt,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, auto_delete_after_days, status, status_changed_at, handle, handle_changed_at
+FROM users
+WHERE id = $1
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.IsChirpyRed,
+		&i.AutoDeleteAfterDays,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.Handle,
+		&i.HandleChangedAt,
+	)
+	return i, err
+}
+
+const getUserByIDForUpdate = `-- name: GetUserByIDForUpdate :one
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, auto_delete_after_days, status, status_changed_at, handle, handle_changed_at
+FROM users
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetUserByIDForUpdate(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByIDForUpdate, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.IsChirpyRed,
+		&i.AutoDeleteAfterDays,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.Handle,
+		&i.HandleChangedAt,
 	)
 	return i, err
 }
 
+const getUserStatus = `-- name: GetUserStatus :one
+SELECT status
+FROM users
+WHERE id = $1
+`
+
+func (q *Queries) GetUserStatus(ctx context.Context, id uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserStatus, id)
+	var status string
+	err := row.Scan(&status)
+	return status, err
+}
+
+const isHandleTaken = `-- name: IsHandleTaken :one
+SELECT EXISTS (
+    SELECT 1
+    FROM users
+    WHERE lower(handle) = lower($1)
+      AND id <> $2
+) OR EXISTS (
+    SELECT 1
+    FROM handle_redirects
+    WHERE handle = lower($1)
+      AND user_id <> $2
+      AND expires_at > NOW()
+)
+`
+
+type IsHandleTakenParams struct {
+	Handle string
+	UserID uuid.UUID
+}
+
+func (q *Queries) IsHandleTaken(ctx context.Context, arg IsHandleTakenParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isHandleTaken, arg.Handle, arg.UserID)
+	var column_1 bool
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const listShadowbannedOrSuspendedUsers = `-- name: ListShadowbannedOrSuspendedUsers :many
+SELECT id
+FROM users
+WHERE id = ANY($1::uuid[])
+  AND status IN ('shadowbanned', 'suspended')
+`
+
+func (q *Queries) ListShadowbannedOrSuspendedUsers(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, listShadowbannedOrSuspendedUsers, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET email = $2, hashed_password = $3
 WHERE id = $1
-RETURNING id, created_at, updated_at, email, is_chirpy_red
+RETURNING id, created_at, updated_at, email, is_chirpy_red, handle
 `
 
 type UpdateUserParams struct {
@@ -91,6 +254,7 @@ type UpdateUserRow struct {
 	UpdatedAt   time.Time
 	Email       string
 	IsChirpyRed bool
+	Handle      string
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateUserRow, error) {
@@ -102,10 +266,91 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateU
 		&i.UpdatedAt,
 		&i.Email,
 		&i.IsChirpyRed,
+		&i.Handle,
+	)
+	return i, err
+}
+
+const updateUserAutoDelete = `-- name: UpdateUserAutoDelete :one
+UPDATE users
+SET auto_delete_after_days = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, auto_delete_after_days, status, status_changed_at, handle, handle_changed_at
+`
+
+type UpdateUserAutoDeleteParams struct {
+	ID                  uuid.UUID
+	AutoDeleteAfterDays sql.NullInt32
+}
+
+func (q *Queries) UpdateUserAutoDelete(ctx context.Context, arg UpdateUserAutoDeleteParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserAutoDelete, arg.ID, arg.AutoDeleteAfterDays)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.IsChirpyRed,
+		&i.AutoDeleteAfterDays,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.Handle,
+		&i.HandleChangedAt,
 	)
 	return i, err
 }
 
+const updateUserHandle = `-- name: UpdateUserHandle :one
+UPDATE users
+SET handle = $2, handle_changed_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, auto_delete_after_days, status, status_changed_at, handle, handle_changed_at
+`
+
+type UpdateUserHandleParams struct {
+	ID     uuid.UUID
+	Handle string
+}
+
+func (q *Queries) UpdateUserHandle(ctx context.Context, arg UpdateUserHandleParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserHandle, arg.ID, arg.Handle)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.IsChirpyRed,
+		&i.AutoDeleteAfterDays,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.Handle,
+		&i.HandleChangedAt,
+	)
+	return i, err
+}
+
+const updateUserStatus = `-- name: UpdateUserStatus :exec
+UPDATE users
+SET status = $2,
+    status_changed_at = CASE WHEN status = $2 THEN status_changed_at ELSE NOW() END,
+    updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateUserStatusParams struct {
+	ID     uuid.UUID
+	Status string
+}
+
+func (q *Queries) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserStatus, arg.ID, arg.Status)
+	return err
+}
+
 const upgradeUserToChirpyRed = `-- name: UpgradeUserToChirpyRed :exec
 UPDATE users
 SET is_chirpy_red = true, updated_at = NOW()
@@ -0,0 +1,187 @@
+package entities
+
+import (
+	"github.com/pedroomedicina/chirpy/internal/handles"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxHashtagLength = 100
+
+// Users are mentioned by handle, as in @alice.
+var mentionPattern = regexp.MustCompile(`@([A-Za-z0-9_]+)`)
+
+var urlPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"]+`)
+
+// Entity is a span of a chirp body. Start and End are code point offsets into
+// the body, with End exclusive.
+type Entity struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// ExtractHashtags finds hashtags such as #golang in text. The returned Text is
+// the tag as written, without the leading '#'.
+func ExtractHashtags(text string) []Entity {
+	runes := []rune(text)
+	urls := ExtractURLs(text)
+	var hashtags []Entity
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '#' || (i > 0 && isTagRune(runes[i-1])) {
+			continue
+		}
+
+		end := i + 1
+		hasLetter := false
+		for end < len(runes) && isTagRune(runes[end]) {
+			if !unicode.IsDigit(runes[end]) && runes[end] != '_' {
+				hasLetter = true
+			}
+			end++
+		}
+
+		length := end - i - 1
+		if hasLetter && length <= maxHashtagLength && !withinAny(urls, i) {
+			hashtags = append(hashtags, Entity{
+				Text:  string(runes[i+1 : end]),
+				Start: i,
+				End:   end,
+			})
+		}
+		i = end - 1
+	}
+
+	return hashtags
+}
+
+// Hashtags returns the distinct normalized hashtags in text, in the order they
+// first appear.
+func Hashtags(text string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, hashtag := range ExtractHashtags(text) {
+		tag := NormalizeHashtag(hashtag.Text)
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// NormalizeHashtag maps the different spellings of a tag, with or without the
+// leading '#', to the form it is stored under.
+func NormalizeHashtag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(tag, "#"))
+}
+
+// ExtractMentions finds mentions such as @alice in text. The returned Text is
+// the mentioned handle, without the leading '@'. Email addresses and runs too
+// long to be a handle are not mentions.
+func ExtractMentions(text string) []Entity {
+	urls := ExtractURLs(text)
+	var mentions []Entity
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(text, -1) {
+		if match[0] > 0 {
+			previous, _ := utf8.DecodeLastRuneInString(text[:match[0]])
+			if isTagRune(previous) || previous == '.' {
+				continue
+			}
+		}
+
+		if match[1] < len(text) {
+			next, _ := utf8.DecodeRuneInString(text[match[1]:])
+			if isTagRune(next) || next == '@' {
+				continue
+			}
+		}
+
+		handle := text[match[2]:match[3]]
+		if len(handle) < handles.MinLength || len(handle) > handles.MaxLength {
+			continue
+		}
+
+		start := utf8.RuneCountInString(text[:match[0]])
+		if withinAny(urls, start) {
+			continue
+		}
+		mentions = append(mentions, Entity{
+			Text:  handle,
+			Start: start,
+			End:   start + utf8.RuneCountInString(text[match[0]:match[1]]),
+		})
+	}
+
+	return mentions
+}
+
+// Mentions returns the distinct folded handles mentioned in text, in the order
+// they first appear.
+func Mentions(text string) []string {
+	var mentioned []string
+	seen := make(map[string]bool)
+	for _, mention := range ExtractMentions(text) {
+		handle := handles.Fold(mention.Text)
+		if seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		mentioned = append(mentioned, handle)
+	}
+
+	return mentioned
+}
+
+// ExtractURLs finds http and https links in text. Trailing punctuation is not
+// considered part of a link, and neither is a closing parenthesis unless the
+// link also contains the opening one.
+func ExtractURLs(text string) []Entity {
+	var urls []Entity
+	for _, match := range urlPattern.FindAllStringIndex(text, -1) {
+		url := trimURL(text[match[0]:match[1]])
+		start := utf8.RuneCountInString(text[:match[0]])
+		urls = append(urls, Entity{
+			Text:  url,
+			Start: start,
+			End:   start + utf8.RuneCountInString(url),
+		})
+	}
+
+	return urls
+}
+
+func trimURL(url string) string {
+	for {
+		last, size := utf8.DecodeLastRuneInString(url)
+		switch {
+		case strings.ContainsRune(".,:;!?'\"]}>", last):
+		case last == ')' && strings.Count(url, "(") < strings.Count(url, ")"):
+		default:
+			return url
+		}
+		url = url[:len(url)-size]
+	}
+}
+
+// withinAny reports whether the code point at offset is part of one of
+// spans. Hashtags and mentions inside links, such as a URL fragment, are part
+// of the link.
+func withinAny(spans []Entity, offset int) bool {
+	for _, span := range spans {
+		if offset >= span.Start && offset < span.End {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_'
+}
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractHashtags(t *testing.T) {
+	hashtags := ExtractHashtags("Loving #Go and #café, not a#tag, #123 or #_")
+	expected := []Entity{
+		{Text: "Go", Start: 7, End: 10},
+		{Text: "café", Start: 15, End: 20},
+	}
+
+	if !reflect.DeepEqual(hashtags, expected) {
+		t.Fatalf("Expected hashtags %+v, got %+v", expected, hashtags)
+	}
+}
+
+func TestHashtagsAreNormalizedAndDistinct(t *testing.T) {
+	tags := Hashtags("#Chirpy is great. #chirpy #GoLang2024")
+	expected := []string{"chirpy", "golang2024"}
+
+	if !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("Expected tags %v, got %v", expected, tags)
+	}
+}
+
+func TestNormalizeHashtag(t *testing.T) {
+	if tag := NormalizeHashtag("#GoLang"); tag != "golang" {
+		t.Fatalf("Expected tag 'golang', got '%s'", tag)
+	}
+}
+
+func TestExtractMentions(t *testing.T) {
+	mentions := ExtractMentions("¡Hola @Alice! cc @bob_2. not bob@example.com, @al or @carol@example.com")
+	expected := []Entity{
+		{Text: "Alice", Start: 6, End: 12},
+		{Text: "bob_2", Start: 17, End: 23},
+	}
+
+	if !reflect.DeepEqual(mentions, expected) {
+		t.Fatalf("Expected mentions %+v, got %+v", expected, mentions)
+	}
+}
+
+func TestMentionsAreNormalizedAndDistinct(t *testing.T) {
+	mentioned := Mentions("@Alice and @ALICE and @bob")
+	expected := []string{"alice", "bob"}
+
+	if !reflect.DeepEqual(mentioned, expected) {
+		t.Fatalf("Expected handles %v, got %v", expected, mentioned)
+	}
+}
+
+func TestExtractURLs(t *testing.T) {
+	urls := ExtractURLs("Read https://go.dev/doc, (see https://en.wikipedia.org/wiki/Go_(game)). ftp://nope")
+	expected := []Entity{
+		{Text: "https://go.dev/doc", Start: 5, End: 23},
+		{Text: "https://en.wikipedia.org/wiki/Go_(game)", Start: 30, End: 69},
+	}
+
+	if !reflect.DeepEqual(urls, expected) {
+		t.Fatalf("Expected urls %+v, got %+v", expected, urls)
+	}
+}
+
+func TestHashtagsAndMentionsInsideURLsAreIgnored(t *testing.T) {
+	text := "#docs at https://example.com/#intro and https://example.com/@bob"
+
+	hashtags := ExtractHashtags(text)
+	if len(hashtags) != 1 || hashtags[0].Text != "docs" {
+		t.Fatalf("Expected only the 'docs' hashtag, got %+v", hashtags)
+	}
+
+	if mentions := ExtractMentions(text); len(mentions) != 0 {
+		t.Fatalf("Expected no mentions, got %+v", mentions)
+	}
+}
@@ -0,0 +1,60 @@
+package entitlements
+
+import (
+	"time"
+)
+
+type Plan string
+
+const (
+	PlanFree      Plan = "free"
+	PlanChirpyRed Plan = "chirpy_red"
+)
+
+// Limits are what a plan allows a user to do.
+type Limits struct {
+	Plan             Plan
+	MaxChirpLength   int
+	MaxMediaPerChirp int
+	EditWindow       time.Duration
+	DailyChirpQuota  int
+	// WritesPerMinute caps how often a user can create or edit chirps and
+	// upload media.
+	WritesPerMinute int
+}
+
+// Plans holds the limits of every plan.
+type Plans struct {
+	Free      Limits
+	ChirpyRed Limits
+}
+
+func DefaultPlans() Plans {
+	return Plans{
+		Free: Limits{
+			Plan:             PlanFree,
+			MaxChirpLength:   140,
+			MaxMediaPerChirp: 4,
+			EditWindow:       15 * time.Minute,
+			DailyChirpQuota:  100,
+			WritesPerMinute:  10,
+		},
+		ChirpyRed: Limits{
+			Plan:             PlanChirpyRed,
+			MaxChirpLength:   1000,
+			MaxMediaPerChirp: 10,
+			EditWindow:       time.Hour,
+			DailyChirpQuota:  1000,
+			WritesPerMinute:  60,
+		},
+	}
+}
+
+// For returns the limits for a user based on their Chirpy Red status.
+func (p Plans) For(isChirpyRed bool) Limits {
+	if isChirpyRed {
+		return p.ChirpyRed
+	}
+
+	return p.Free
+}
@@ -0,0 +1,27 @@
+package entitlements
+
+import (
+	"testing"
+)
+
+func TestPlansFor(t *testing.T) {
+	plans := DefaultPlans()
+
+	free := plans.For(false)
+	if free.Plan != PlanFree || free.MaxChirpLength != 140 {
+		t.Fatalf("Expected the free plan with 140 characters, got %+v", free)
+	}
+
+	red := plans.For(true)
+	if red.Plan != PlanChirpyRed {
+		t.Fatalf("Expected the Chirpy Red plan, got %s", red.Plan)
+	}
+
+	if red.MaxChirpLength <= free.MaxChirpLength ||
+		red.MaxMediaPerChirp <= free.MaxMediaPerChirp ||
+		red.EditWindow <= free.EditWindow ||
+		red.DailyChirpQuota <= free.DailyChirpQuota ||
+		red.WritesPerMinute <= free.WritesPerMinute {
+		t.Fatalf("Expected every Chirpy Red limit to exceed the free plan, got %+v and %+v", red, free)
+	}
+}
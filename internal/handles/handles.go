@@ -0,0 +1,63 @@
+package handles
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	MinLength = 3
+	MaxLength = 30
+)
+
+// reserved handles would be confused with the service itself.
+var reserved = map[string]bool{
+	"admin":     true,
+	"api":       true,
+	"chirpy":    true,
+	"moderator": true,
+	"root":      true,
+	"support":   true,
+}
+
+var (
+	ErrLength     = fmt.Errorf("Handles must be %d to %d characters long", MinLength, MaxLength)
+	ErrCharacters = errors.New("Handles can only contain letters, digits and underscores")
+	ErrReserved   = errors.New("Handle is reserved")
+)
+
+// Clean strips the surrounding whitespace and leading '@' people tend to type
+// with a handle. Case is kept as written.
+func Clean(handle string) string {
+	return strings.TrimPrefix(strings.TrimSpace(handle), "@")
+}
+
+// Fold is the form handles are compared in, so that Alice and alice are the
+// same handle.
+func Fold(handle string) string {
+	return strings.ToLower(handle)
+}
+
+// Validate checks that a cleaned handle can be chosen.
+func Validate(handle string) error {
+	if len(handle) < MinLength || len(handle) > MaxLength {
+		return ErrLength
+	}
+
+	for _, r := range handle {
+		if !isHandleRune(r) {
+			return ErrCharacters
+		}
+	}
+
+	if reserved[Fold(handle)] {
+		return ErrReserved
+	}
+
+	return nil
+}
+
+func isHandleRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
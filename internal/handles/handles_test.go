@@ -0,0 +1,34 @@
+package handles
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClean(t *testing.T) {
+	if handle := Clean("  @Alice_99 "); handle != "Alice_99" {
+		t.Fatalf("Expected handle 'Alice_99', got '%s'", handle)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		handle   string
+		expected error
+	}{
+		{"alice", nil},
+		{"Alice_99", nil},
+		{"bo", ErrLength},
+		{"a_handle_that_is_far_too_long_x", ErrLength},
+		{"alice.smith", ErrCharacters},
+		{"álice", ErrCharacters},
+		{"Admin", ErrReserved},
+	}
+
+	for _, test := range tests {
+		err := Validate(test.handle)
+		if !errors.Is(err, test.expected) {
+			t.Errorf("Validate(%q): expected %v, got %v", test.handle, test.expected, err)
+		}
+	}
+}
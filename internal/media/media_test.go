@@ -0,0 +1,252 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcessPNG(t *testing.T) {
+	processed, err := Process(bytes.NewReader(encodePNG(t, 800, 400)))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if processed.ContentType != "image/png" || processed.Extension != ".png" {
+		t.Fatalf("Expected a png, got %s with extension %s", processed.ContentType, processed.Extension)
+	}
+
+	if processed.Width != 800 || processed.Height != 400 {
+		t.Fatalf("Expected 800x400, got %dx%d", processed.Width, processed.Height)
+	}
+
+	thumb, err := png.Decode(bytes.NewReader(processed.Thumbnail))
+	if err != nil {
+		t.Fatalf("Expected a valid thumbnail, got %v", err)
+	}
+
+	if thumb.Bounds().Dx() != thumbnailSize || thumb.Bounds().Dy() != thumbnailSize/2 {
+		t.Fatalf("Expected a %dx%d thumbnail, got %v", thumbnailSize, thumbnailSize/2, thumb.Bounds())
+	}
+}
+
+func TestProcessStripsJPEGMetadata(t *testing.T) {
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 10, 10)), nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Splice an APP1 (EXIF) segment in right after the SOI marker.
+	exif := append([]byte{0xFF, 0xE1, 0x00, 0x10}, []byte("Exif\x00\x00GPS-DATA!")...)
+	original := append(append([]byte{}, buf.Bytes()[:2]...), append(exif, buf.Bytes()[2:]...)...)
+
+	processed, err := Process(bytes.NewReader(original))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if bytes.Contains(processed.Data, []byte("Exif")) {
+		t.Fatal("Expected EXIF data to be stripped")
+	}
+}
+
+func TestProcessAppliesJPEGOrientation(t *testing.T) {
+	// A landscape photo, white on the left and black on the right, tagged as
+	// needing a 90° clockwise turn, as phones store portrait shots.
+	img := image.NewGray(image.Rect(0, 0, 64, 32))
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x++ {
+			img.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	exif := []byte{
+		0xFF, 0xE1, 0x00, 0x22,
+		'E', 'x', 'i', 'f', 0x00, 0x00,
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08,
+		0x00, 0x01,
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, 0x06, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	}
+	original := append(append([]byte{}, buf.Bytes()[:2]...), append(exif, buf.Bytes()[2:]...)...)
+
+	processed, err := Process(bytes.NewReader(original))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if processed.Width != 32 || processed.Height != 64 {
+		t.Fatalf("Expected 32x64, got %dx%d", processed.Width, processed.Height)
+	}
+
+	stored, err := jpeg.Decode(bytes.NewReader(processed.Data))
+	if err != nil {
+		t.Fatalf("Expected a valid image, got %v", err)
+	}
+
+	if stored.Bounds().Dx() != 32 || stored.Bounds().Dy() != 64 {
+		t.Fatalf("Expected a 32x64 image, got %v", stored.Bounds())
+	}
+
+	// Turned clockwise, the white left half ends up on top.
+	top := color.GrayModel.Convert(stored.At(16, 16)).(color.Gray)
+	bottom := color.GrayModel.Convert(stored.At(16, 48)).(color.Gray)
+	if top.Y < 200 || bottom.Y > 55 {
+		t.Fatalf("Expected white on top and black below, got %d and %d", top.Y, bottom.Y)
+	}
+
+	thumb, err := jpeg.Decode(bytes.NewReader(processed.Thumbnail))
+	if err != nil {
+		t.Fatalf("Expected a valid thumbnail, got %v", err)
+	}
+
+	if thumb.Bounds().Dx() != 32 || thumb.Bounds().Dy() != 64 {
+		t.Fatalf("Expected a 32x64 thumbnail, got %v", thumb.Bounds())
+	}
+}
+
+func TestProcessRejectsUnsupportedTypes(t *testing.T) {
+	_, err := Process(strings.NewReader("just some text, not an image"))
+	if !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("Expected ErrUnsupportedType, got %v", err)
+	}
+}
+
+func TestProcessRejectsLargeFiles(t *testing.T) {
+	_, err := Process(bytes.NewReader(make([]byte, MaxUploadBytes+1)))
+	if !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("Expected ErrTooLarge, got %v", err)
+	}
+}
+
+func TestProcessRejectsGIFsWithTooManyFrames(t *testing.T) {
+	// The same small frame repeated keeps the file well under the upload
+	// limit, but decoding it would allocate every frame separately.
+	frame := image.NewPaletted(image.Rect(0, 0, 100, 100), color.Palette{color.Black, color.White})
+	animation := &gif.GIF{}
+	for i := 0; i <= maxPixels/(100*100); i++ {
+		animation.Image = append(animation.Image, frame)
+		animation.Delay = append(animation.Delay, 0)
+	}
+
+	var buf bytes.Buffer
+	err := gif.EncodeAll(&buf, animation)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if buf.Len() > MaxUploadBytes {
+		t.Fatalf("Expected the GIF to fit in an upload, got %d bytes", buf.Len())
+	}
+
+	_, err = Process(bytes.NewReader(buf.Bytes()))
+	if !errors.Is(err, ErrInvalidImage) {
+		t.Fatalf("Expected ErrInvalidImage, got %v", err)
+	}
+
+	pixels, err := gifPixels(buf.Bytes())
+	if err != nil || pixels != len(animation.Image)*100*100 {
+		t.Fatalf("Expected %d pixels, got %d (%v)", len(animation.Image)*100*100, pixels, err)
+	}
+}
+
+func TestProcessGIF(t *testing.T) {
+	frame := image.NewPaletted(image.Rect(0, 0, 20, 10), color.Palette{color.Black, color.White})
+	animation := &gif.GIF{
+		Image: []*image.Paletted{frame, frame, frame},
+		Delay: []int{10, 10, 10},
+	}
+
+	var buf bytes.Buffer
+	err := gif.EncodeAll(&buf, animation)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	processed, err := Process(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if processed.Extension != ".gif" || processed.Width != 20 || processed.Height != 10 {
+		t.Fatalf("Expected a 20x10 gif, got %s %dx%d", processed.Extension, processed.Width, processed.Height)
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := NewLocalStorage(dir, "/media/")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	err = storage.Save(context.Background(), "picture.png", strings.NewReader("data"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if url := storage.URL("picture.png"); url != "/media/picture.png" {
+		t.Fatalf("Expected URL '/media/picture.png', got '%s'", url)
+	}
+
+	_, err = storage.FileSystem().Open("/picture.png")
+	if err != nil {
+		t.Fatalf("Expected the file to be served, got %v", err)
+	}
+
+	_, err = storage.FileSystem().Open("/")
+	if err == nil {
+		t.Fatal("Expected directories to be hidden, got none")
+	}
+
+	err = storage.Save(context.Background(), "../escape.png", strings.NewReader("data"))
+	if err == nil {
+		t.Fatal("Expected an error for a key outside the storage directory, got none")
+	}
+
+	err = storage.Delete(context.Background(), "picture.png")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "picture.png"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected the file to be deleted, got %v", err)
+	}
+
+	var _ http.FileSystem = storage.FileSystem()
+}
@@ -0,0 +1,120 @@
+package media
+
+import (
+	"encoding/binary"
+	"image"
+)
+
+// jpegOrientation reads the EXIF Orientation tag of a JPEG, from 1 (upright)
+// to 8. Files without one, or with EXIF data that cannot be parsed, are
+// treated as upright.
+func jpegOrientation(data []byte) int {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	i := 2
+	for i+4 <= len(data) && data[i] == 0xFF {
+		marker := data[i+1]
+		// Start of scan: the metadata segments are all behind us.
+		if marker == 0xDA {
+			break
+		}
+
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			break
+		}
+
+		segment := data[i+4 : i+2+size]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + size
+	}
+
+	return 1
+}
+
+// exifOrientation finds the Orientation tag in the first IFD of a TIFF
+// structure, as carried in a JPEG's EXIF segment.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+	for n := 0; n < entries; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			break
+		}
+
+		// Tag 0x0112 is Orientation, stored as a single SHORT (type 3).
+		if order.Uint16(tiff[entry:]) == 0x0112 && order.Uint16(tiff[entry+2:]) == 3 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+
+	return 1
+}
+
+// applyOrientation rotates and flips img so it displays upright without its
+// EXIF Orientation tag. Orientations 5 to 8 swap the width and height.
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			var srcX, srcY int
+			switch orientation {
+			case 2: // Mirrored horizontally.
+				srcX, srcY = width-1-x, y
+			case 3: // Rotated 180°.
+				srcX, srcY = width-1-x, height-1-y
+			case 4: // Mirrored vertically.
+				srcX, srcY = x, height-1-y
+			case 5: // Mirrored along the top-left diagonal.
+				srcX, srcY = y, x
+			case 6: // Needs a 90° clockwise turn.
+				srcX, srcY = y, height-1-x
+			case 7: // Mirrored along the top-right diagonal.
+				srcX, srcY = width-1-y, height-1-x
+			case 8: // Needs a 90° counterclockwise turn.
+				srcX, srcY = width-1-y, x
+			}
+			dst.Set(x, y, img.At(bounds.Min.X+srcX, bounds.Min.Y+srcY))
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,240 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+)
+
+const (
+	MaxUploadBytes = 5 << 20
+	maxPixels      = 40_000_000
+	thumbnailSize  = 320
+)
+
+var (
+	ErrTooLarge        = errors.New("media file is too large")
+	ErrUnsupportedType = errors.New("unsupported media type")
+	ErrInvalidImage    = errors.New("invalid image")
+)
+
+// Processed is an uploaded image after it has been re-encoded, which drops
+// EXIF and any other metadata the original file carried. JPEGs are turned
+// upright first, since their EXIF orientation is lost with the rest.
+type Processed struct {
+	ContentType        string
+	Extension          string
+	Data               []byte
+	Width              int
+	Height             int
+	Thumbnail          []byte
+	ThumbnailExtension string
+}
+
+// Process validates an uploaded image and produces the files to store for it.
+func Process(r io.Reader) (*Processed, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxUploadBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > MaxUploadBytes {
+		return nil, ErrTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return nil, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, ErrInvalidImage
+	}
+
+	processed := &Processed{
+		ContentType: contentType,
+		Width:       config.Width,
+		Height:      config.Height,
+	}
+
+	var firstFrame image.Image
+	var encoded bytes.Buffer
+	switch contentType {
+	case "image/jpeg":
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrInvalidImage
+		}
+		img = applyOrientation(img, jpegOrientation(data))
+		processed.Width, processed.Height = img.Bounds().Dx(), img.Bounds().Dy()
+		firstFrame = img
+		processed.Extension = ".jpg"
+		err = jpeg.Encode(&encoded, img, &jpeg.Options{Quality: 90})
+		if err != nil {
+			return nil, err
+		}
+	case "image/png":
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrInvalidImage
+		}
+		firstFrame = img
+		processed.Extension = ".png"
+		err = png.Encode(&encoded, img)
+		if err != nil {
+			return nil, err
+		}
+	case "image/gif":
+		// Every frame is decoded into its own image, so the limit applies to
+		// the frames added together rather than to the screen size.
+		pixels, err := gifPixels(data)
+		if err != nil || pixels > maxPixels {
+			return nil, ErrInvalidImage
+		}
+		animation, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil || len(animation.Image) == 0 {
+			return nil, ErrInvalidImage
+		}
+		firstFrame = animation.Image[0]
+		processed.Extension = ".gif"
+		err = gif.EncodeAll(&encoded, animation)
+		if err != nil {
+			return nil, err
+		}
+	}
+	processed.Data = encoded.Bytes()
+
+	var thumbnail bytes.Buffer
+	if contentType == "image/jpeg" {
+		processed.ThumbnailExtension = ".jpg"
+		err = jpeg.Encode(&thumbnail, Thumbnail(firstFrame, thumbnailSize), &jpeg.Options{Quality: 80})
+	} else {
+		processed.ThumbnailExtension = ".png"
+		err = png.Encode(&thumbnail, Thumbnail(firstFrame, thumbnailSize))
+	}
+	if err != nil {
+		return nil, err
+	}
+	processed.Thumbnail = thumbnail.Bytes()
+
+	return processed, nil
+}
+
+// gifPixels adds up the areas of the frames in a GIF by walking its blocks,
+// without decoding any image data. A file that ends early is left for the
+// decoder to reject.
+func gifPixels(data []byte) (int, error) {
+	const headerSize = 13
+	if len(data) < headerSize {
+		return 0, ErrInvalidImage
+	}
+
+	i := headerSize
+	if data[10]&0x80 != 0 {
+		i += 3 << ((data[10] & 0x07) + 1)
+	}
+
+	pixels := 0
+	for i < len(data) {
+		switch data[i] {
+		case 0x21: // Extension: introducer, label, sub-blocks.
+			i = skipGIFSubBlocks(data, i+2)
+		case 0x2C: // Image descriptor, local color table, LZW code size, sub-blocks.
+			if i+10 > len(data) {
+				return pixels, nil
+			}
+			width := int(data[i+5]) | int(data[i+6])<<8
+			height := int(data[i+7]) | int(data[i+8])<<8
+			pixels += width * height
+			packed := data[i+9]
+			i += 10
+			if packed&0x80 != 0 {
+				i += 3 << ((packed & 0x07) + 1)
+			}
+			i = skipGIFSubBlocks(data, i+1)
+		case 0x3B: // Trailer.
+			return pixels, nil
+		default:
+			return 0, ErrInvalidImage
+		}
+	}
+
+	return pixels, nil
+}
+
+// skipGIFSubBlocks returns the offset just past the run of data sub-blocks
+// starting at i.
+func skipGIFSubBlocks(data []byte, i int) int {
+	for i < len(data) {
+		size := int(data[i])
+		i++
+		if size == 0 {
+			break
+		}
+		i += size
+	}
+
+	return i
+}
+
+// Thumbnail scales img down so that neither side exceeds size, averaging the
+// source pixels that fall into each thumbnail pixel. Smaller images are
+// returned unchanged.
+func Thumbnail(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= size && height <= size {
+		return img
+	}
+
+	thumbWidth, thumbHeight := size, size
+	if width > height {
+		thumbHeight = max(1, height*size/width)
+	} else {
+		thumbWidth = max(1, width*size/height)
+	}
+
+	thumb := image.NewNRGBA(image.Rect(0, 0, thumbWidth, thumbHeight))
+	for y := 0; y < thumbHeight; y++ {
+		srcY0 := bounds.Min.Y + y*height/thumbHeight
+		srcY1 := max(srcY0+1, bounds.Min.Y+(y+1)*height/thumbHeight)
+		for x := 0; x < thumbWidth; x++ {
+			srcX0 := bounds.Min.X + x*width/thumbWidth
+			srcX1 := max(srcX0+1, bounds.Min.X+(x+1)*width/thumbWidth)
+
+			var r, g, b, a, count uint64
+			for sy := srcY0; sy < srcY1; sy++ {
+				for sx := srcX0; sx < srcX1; sx++ {
+					pixel := color.NRGBA64Model.Convert(img.At(sx, sy)).(color.NRGBA64)
+					r += uint64(pixel.R)
+					g += uint64(pixel.G)
+					b += uint64(pixel.B)
+					a += uint64(pixel.A)
+					count++
+				}
+			}
+
+			thumb.Set(x, y, color.NRGBA64{
+				R: uint16(r / count),
+				G: uint16(g / count),
+				B: uint16(b / count),
+				A: uint16(a / count),
+			})
+		}
+	}
+
+	return thumb
+}
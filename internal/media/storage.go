@@ -0,0 +1,112 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Storage keeps uploaded media files and knows the URL they are served from.
+type Storage interface {
+	Save(ctx context.Context, key string, r io.Reader) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// LocalStorage stores media files in a directory on the local disk.
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocalStorage(dir, baseURL string) (*LocalStorage, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalStorage{dir: dir, baseURL: baseURL}, nil
+}
+
+func (s *LocalStorage) Save(_ context.Context, key string, r io.Reader) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	// Files are written under a temporary name first so a failed upload never
+	// leaves a partial file behind a servable key.
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, r)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filePath)
+}
+
+func (s *LocalStorage) Delete(_ context.Context, key string) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return path.Join(s.baseURL, key)
+}
+
+// FileSystem exposes the stored files for an http.FileServer. Directories are
+// hidden so the server cannot be used to list every upload.
+func (s *LocalStorage) FileSystem() http.FileSystem {
+	return filesOnly{http.Dir(s.dir)}
+}
+
+func (s *LocalStorage) path(key string) (string, error) {
+	if key == "" || filepath.Base(key) != key || key[0] == '.' {
+		return "", errors.New("invalid storage key")
+	}
+
+	return filepath.Join(s.dir, key), nil
+}
+
+type filesOnly struct {
+	fs http.FileSystem
+}
+
+func (f filesOnly) Open(name string) (http.File, error) {
+	file, err := f.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := file.Stat()
+	if err != nil || stat.IsDir() || path.Base(name)[0] == '.' {
+		file.Close()
+		return nil, os.ErrNotExist
+	}
+
+	return file, nil
+}
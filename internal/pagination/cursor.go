@@ -0,0 +1,120 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Cursor identifies the last row of a page in a created_at+id keyset ordering.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// RankCursor identifies the last row of a page ordered by relevance, with
+// created_at+id breaking ties between equally ranked rows.
+type RankCursor struct {
+	Rank float32
+	Cursor
+}
+
+func EncodeCursor(cursor Cursor) string {
+	return encodeParts(cursor.CreatedAt.Format(time.RFC3339Nano), cursor.ID.String())
+}
+
+func DecodeCursor(encoded string) (Cursor, error) {
+	parts, err := decodeParts(encoded, 2)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	return parseCursor(parts[0], parts[1])
+}
+
+func EncodeRankCursor(cursor RankCursor) string {
+	return encodeParts(
+		strconv.FormatFloat(float64(cursor.Rank), 'g', -1, 32),
+		cursor.CreatedAt.Format(time.RFC3339Nano),
+		cursor.ID.String(),
+	)
+}
+
+func DecodeRankCursor(encoded string) (RankCursor, error) {
+	parts, err := decodeParts(encoded, 3)
+	if err != nil {
+		return RankCursor{}, err
+	}
+
+	rank, err := strconv.ParseFloat(parts[0], 32)
+	if err != nil {
+		return RankCursor{}, errors.New("invalid cursor")
+	}
+
+	cursor, err := parseCursor(parts[1], parts[2])
+	if err != nil {
+		return RankCursor{}, err
+	}
+
+	return RankCursor{Rank: float32(rank), Cursor: cursor}, nil
+}
+
+func encodeParts(parts ...string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(parts, "|")))
+}
+
+func decodeParts(encoded string, count int) ([]string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != count {
+		return nil, errors.New("invalid cursor")
+	}
+
+	return parts, nil
+}
+
+func parseCursor(createdAtString, idString string) (Cursor, error) {
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtString)
+	if err != nil {
+		return Cursor{}, errors.New("invalid cursor")
+	}
+
+	id, err := uuid.Parse(idString)
+	if err != nil {
+		return Cursor{}, errors.New("invalid cursor")
+	}
+
+	return Cursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// ParseLimit parses the limit query parameter, falling back to DefaultLimit
+// when it is empty.
+func ParseLimit(limit string) (int32, error) {
+	if limit == "" {
+		return DefaultLimit, nil
+	}
+
+	parsed, err := strconv.Atoi(limit)
+	if err != nil || parsed < 1 {
+		return 0, fmt.Errorf("invalid limit: %s", limit)
+	}
+
+	if parsed > MaxLimit {
+		return MaxLimit, nil
+	}
+
+	return int32(parsed), nil
+}
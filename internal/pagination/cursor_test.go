@@ -0,0 +1,89 @@
+package pagination
+
+import (
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{
+		CreatedAt: time.Date(2024, 1, 1, 12, 30, 0, 123456000, time.UTC),
+		ID:        uuid.New(),
+	}
+
+	decoded, err := DecodeCursor(EncodeCursor(cursor))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !decoded.CreatedAt.Equal(cursor.CreatedAt) || decoded.ID != cursor.ID {
+		t.Fatalf("Expected cursor %+v, got %+v", cursor, decoded)
+	}
+}
+
+func TestRankCursorRoundTrip(t *testing.T) {
+	cursor := RankCursor{
+		Rank: 0.0607927,
+		Cursor: Cursor{
+			CreatedAt: time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC),
+			ID:        uuid.New(),
+		},
+	}
+
+	decoded, err := DecodeRankCursor(EncodeRankCursor(cursor))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if decoded.Rank != cursor.Rank || !decoded.CreatedAt.Equal(cursor.CreatedAt) || decoded.ID != cursor.ID {
+		t.Fatalf("Expected cursor %+v, got %+v", cursor, decoded)
+	}
+
+	_, err = DecodeRankCursor(EncodeCursor(cursor.Cursor))
+	if err == nil {
+		t.Fatal("Expected an error decoding a plain cursor as a rank cursor, got none")
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	for _, encoded := range []string{"not-base64!", "bm8tc2VwYXJhdG9y", EncodeCursor(Cursor{})[:10]} {
+		_, err := DecodeCursor(encoded)
+		if err == nil {
+			t.Fatalf("Expected an error for cursor %q, got none", encoded)
+		}
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int32
+		wantErr  bool
+	}{
+		{input: "", expected: DefaultLimit},
+		{input: "5", expected: 5},
+		{input: "1000", expected: MaxLimit},
+		{input: "0", wantErr: true},
+		{input: "-3", wantErr: true},
+		{input: "ten", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		limit, err := ParseLimit(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("Expected an error for limit %q, got none", tt.input)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Expected no error for limit %q, got %v", tt.input, err)
+		}
+
+		if limit != tt.expected {
+			t.Fatalf("Expected limit %d for %q, got %d", tt.expected, tt.input, limit)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter counts events per key in fixed windows. Counts live in memory, so
+// each server instance enforces its limits separately.
+type Limiter struct {
+	mu      sync.Mutex
+	window  time.Duration
+	buckets map[string]bucket
+	sweptAt time.Time
+}
+
+type bucket struct {
+	start time.Time
+	count int
+}
+
+func New(window time.Duration) *Limiter {
+	return &Limiter{
+		window:  window,
+		buckets: make(map[string]bucket),
+	}
+}
+
+// Allow records an event for key and reports whether it stays within limit
+// events for the current window. When it does not, it also returns how long
+// until the window resets.
+func (l *Limiter) Allow(key string, limit int, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok || now.Sub(b.start) >= l.window {
+		b = bucket{start: now}
+	}
+
+	if b.count >= limit {
+		return false, b.start.Add(l.window).Sub(now)
+	}
+
+	b.count++
+	l.buckets[key] = b
+	return true, 0
+}
+
+// sweep drops expired buckets once per window so idle keys do not pile up.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.sweptAt) < l.window {
+		return
+	}
+
+	for key, b := range l.buckets {
+		if now.Sub(b.start) >= l.window {
+			delete(l.buckets, key)
+		}
+	}
+	l.sweptAt = now
+}
@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	limiter := New(time.Minute)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		allowed, _ := limiter.Allow("alice", 3, now.Add(time.Duration(i)*time.Second))
+		if !allowed {
+			t.Fatalf("Expected event %d to be allowed", i+1)
+		}
+	}
+
+	allowed, retryAfter := limiter.Allow("alice", 3, now.Add(10*time.Second))
+	if allowed {
+		t.Fatal("Expected the fourth event to be rejected")
+	}
+
+	if retryAfter != 50*time.Second {
+		t.Fatalf("Expected to retry after 50s, got %v", retryAfter)
+	}
+
+	allowed, _ = limiter.Allow("bob", 3, now.Add(10*time.Second))
+	if !allowed {
+		t.Fatal("Expected other keys to have their own limit")
+	}
+
+	allowed, _ = limiter.Allow("alice", 3, now.Add(time.Minute))
+	if !allowed {
+		t.Fatal("Expected the limit to reset with the next window")
+	}
+}
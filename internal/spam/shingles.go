@@ -0,0 +1,55 @@
+package spam
+
+import (
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+// shingleSize is the number of consecutive words in a shingle.
+const shingleSize = 3
+
+// Shingles hashes every run of shingleSize consecutive words in text, ignoring
+// case and punctuation. Texts shorter than that yield a single shingle of all
+// their words.
+func Shingles(text string) map[uint64]bool {
+	words := words(text)
+
+	shingles := make(map[uint64]bool)
+	if len(words) == 0 {
+		return shingles
+	}
+
+	for i := 0; i+shingleSize <= len(words) || i == 0; i++ {
+		end := min(i+shingleSize, len(words))
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:end], " ")))
+		shingles[h.Sum64()] = true
+	}
+
+	return shingles
+}
+
+// words splits text into lowercase words, dropping punctuation.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Jaccard returns the share of shingles the two sets have in common, from 0
+// for nothing in common to 1 for identical sets.
+func Jaccard(a, b map[uint64]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for shingle := range a {
+		if b[shingle] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
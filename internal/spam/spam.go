@@ -0,0 +1,154 @@
+package spam
+
+import (
+	"fmt"
+	"github.com/pedroomedicina/chirpy/internal/entities"
+	"time"
+)
+
+type Decision int
+
+const (
+	Allow Decision = iota
+	Hold
+	Reject
+)
+
+func (d Decision) String() string {
+	switch d {
+	case Hold:
+		return "hold"
+	case Reject:
+		return "reject"
+	default:
+		return "allow"
+	}
+}
+
+// Config sets how each signal is detected and weighed. A chirp is held for
+// moderation once its score reaches HoldScore and rejected once it reaches
+// RejectScore.
+type Config struct {
+	// A chirp at least DuplicateSimilarity similar to one the author posted
+	// within DuplicateWindow scores DuplicateWeight times the similarity.
+	// Chirps shorter than a shingle, such as "gm", are never duplicates:
+	// repeating them is ordinary.
+	DuplicateWindow     time.Duration
+	DuplicateSimilarity float64
+	DuplicateWeight     float64
+
+	// Every chirp past BurstLimit within BurstWindow scores BurstWeight.
+	BurstWindow time.Duration
+	BurstLimit  int
+	BurstWeight float64
+
+	// Accounts younger than NewAccountAge score LinkWeight for every link
+	// past NewAccountMaxLinks.
+	NewAccountAge      time.Duration
+	NewAccountMaxLinks int
+	LinkWeight         float64
+
+	// Every distinct mention past MaxMentions scores MentionWeight.
+	MaxMentions   int
+	MentionWeight float64
+
+	// Every flag raised by the content filters scores FlagWeight.
+	FlagWeight float64
+
+	HoldScore   float64
+	RejectScore float64
+}
+
+func DefaultConfig() Config {
+	return Config{
+		DuplicateWindow:     24 * time.Hour,
+		DuplicateSimilarity: 0.8,
+		DuplicateWeight:     3,
+		BurstWindow:         time.Minute,
+		BurstLimit:          5,
+		BurstWeight:         1,
+		NewAccountAge:       24 * time.Hour,
+		NewAccountMaxLinks:  1,
+		LinkWeight:          2,
+		MaxMentions:         5,
+		MentionWeight:       1,
+		FlagWeight:          1,
+		HoldScore:           3,
+		RejectScore:         6,
+	}
+}
+
+// Input describes a chirp about to be created and its author's recent activity.
+type Input struct {
+	Body string
+	// AccountAge is how long ago the author signed up.
+	AccountAge time.Duration
+	// RecentChirps counts the author's chirps within the burst window, not
+	// including this one.
+	RecentChirps int
+	// RecentBodies holds the author's chirps within the duplicate window.
+	RecentBodies []string
+	ContentFlags int
+}
+
+type Signal struct {
+	Name   string
+	Score  float64
+	Detail string
+}
+
+type Result struct {
+	Score    float64
+	Signals  []Signal
+	Decision Decision
+}
+
+// Score runs every signal against the input and adds up their scores.
+func (c Config) Score(in Input) Result {
+	var result Result
+
+	add := func(name string, score float64, detail string) {
+		result.Score += score
+		result.Signals = append(result.Signals, Signal{Name: name, Score: score, Detail: detail})
+	}
+
+	var similarity float64
+	if len(words(in.Body)) >= shingleSize {
+		shingles := Shingles(in.Body)
+		for _, body := range in.RecentBodies {
+			similarity = max(similarity, Jaccard(shingles, Shingles(body)))
+		}
+	}
+	if similarity >= c.DuplicateSimilarity {
+		add("duplicate", c.DuplicateWeight*similarity, fmt.Sprintf("%.0f%% similar to a recent chirp", similarity*100))
+	}
+
+	if over := in.RecentChirps + 1 - c.BurstLimit; over > 0 {
+		add("burst", c.BurstWeight*float64(over), fmt.Sprintf("%d chirps within %s", in.RecentChirps+1, c.BurstWindow))
+	}
+
+	if in.AccountAge < c.NewAccountAge {
+		links := len(entities.ExtractURLs(in.Body))
+		if over := links - c.NewAccountMaxLinks; over > 0 {
+			add("links", c.LinkWeight*float64(over), fmt.Sprintf("%d links from an account younger than %s", links, c.NewAccountAge))
+		}
+	}
+
+	mentions := len(entities.Mentions(in.Body))
+	if over := mentions - c.MaxMentions; over > 0 {
+		add("mentions", c.MentionWeight*float64(over), fmt.Sprintf("%d mentions", mentions))
+	}
+
+	if in.ContentFlags > 0 {
+		add("content_flags", c.FlagWeight*float64(in.ContentFlags), fmt.Sprintf("%d content filter flags", in.ContentFlags))
+	}
+
+	switch {
+	case result.Score >= c.RejectScore:
+		result.Decision = Reject
+	case result.Score >= c.HoldScore:
+		result.Decision = Hold
+	}
+
+	return result
+}
@@ -0,0 +1,96 @@
+package spam
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJaccard(t *testing.T) {
+	a := Shingles("Buy cheap followers now at my site, limited offer")
+	if got := Jaccard(a, Shingles("buy CHEAP followers now at my site... limited offer!")); got != 1 {
+		t.Errorf("Expected identical texts to have similarity 1, got %v", got)
+	}
+
+	got := Jaccard(a, Shingles("Buy cheap followers now at my site, limited time offer"))
+	if got < 0.5 || got >= 1 {
+		t.Errorf("Expected a small edit to stay similar, got %v", got)
+	}
+
+	if got := Jaccard(a, Shingles("What a lovely day for a walk in the park")); got != 0 {
+		t.Errorf("Expected unrelated texts to have similarity 0, got %v", got)
+	}
+
+	if got := Jaccard(Shingles("hi"), Shingles("Hi!")); got != 1 {
+		t.Errorf("Expected short texts to be compared whole, got %v", got)
+	}
+}
+
+func TestScoreAllowsOrdinaryChirps(t *testing.T) {
+	result := DefaultConfig().Score(Input{
+		Body:         "Morning! See https://example.com for the details",
+		AccountAge:   time.Hour,
+		RecentChirps: 2,
+		RecentBodies: []string{"Good night everyone"},
+	})
+
+	if result.Decision != Allow || result.Score != 0 || len(result.Signals) != 0 {
+		t.Errorf("Expected an ordinary chirp to be allowed, got %+v", result)
+	}
+}
+
+func TestScoreHoldsDuplicates(t *testing.T) {
+	result := DefaultConfig().Score(Input{
+		Body:         "Check out my amazing new product today",
+		AccountAge:   30 * 24 * time.Hour,
+		RecentBodies: []string{"check out my amazing new product today!!"},
+	})
+
+	if result.Decision != Hold || len(result.Signals) != 1 || result.Signals[0].Name != "duplicate" {
+		t.Errorf("Expected a duplicate to be held, got %+v", result)
+	}
+}
+
+func TestScoreAllowsRepeatedShortChirps(t *testing.T) {
+	result := DefaultConfig().Score(Input{
+		Body:         "gm",
+		AccountAge:   30 * 24 * time.Hour,
+		RecentBodies: []string{"GM!", "lol"},
+	})
+
+	if result.Decision != Allow || len(result.Signals) != 0 {
+		t.Errorf("Expected a repeated short chirp to be allowed, got %+v", result)
+	}
+}
+
+func TestScoreRejectsCombinedSignals(t *testing.T) {
+	config := DefaultConfig()
+	result := config.Score(Input{
+		Body:         "https://a.example https://b.example https://c.example @ann @bob @cat @dan @eve @fay",
+		AccountAge:   time.Hour,
+		RecentChirps: config.BurstLimit,
+	})
+
+	names := map[string]bool{}
+	for _, signal := range result.Signals {
+		names[signal.Name] = true
+	}
+	if !names["links"] || !names["mentions"] || !names["burst"] {
+		t.Fatalf("Expected links, mentions and burst signals, got %+v", result.Signals)
+	}
+
+	// Two extra links, one extra mention and one chirp over the burst limit.
+	if result.Score != 6 || result.Decision != Reject {
+		t.Errorf("Expected a score of 6 and a rejection, got %+v", result)
+	}
+}
+
+func TestScoreIgnoresLinksFromEstablishedAccounts(t *testing.T) {
+	result := DefaultConfig().Score(Input{
+		Body:       "https://a.example https://b.example https://c.example",
+		AccountAge: 48 * time.Hour,
+	})
+
+	if result.Decision != Allow {
+		t.Errorf("Expected links from an established account to be allowed, got %+v", result)
+	}
+}
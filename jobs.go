@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// runBatchJob calls job every interval until ctx is cancelled. job reports how
+// many rows it handled; while it keeps handling full batches it is called
+// again straight away instead of waiting for the next tick.
+func runBatchJob(ctx context.Context, name string, interval time.Duration, batchSize int, job func(context.Context) (int, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			handled, err := job(ctx)
+			if err != nil {
+				log.Printf("Error running %s: %v", name, err)
+				break
+			}
+
+			if handled < batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/pagination"
+	"net/http"
+)
+
+func (cfg *apiConfig) handleLikeChirp(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	dbChirp, ok := cfg.lookupChirp(w, r)
+	if !ok {
+		return
+	}
+
+	err = cfg.dbQueries.CreateChirpLike(r.Context(), database.CreateChirpLikeParams{
+		UserID:  userID,
+		ChirpID: dbChirp.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handleUnlikeChirp(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	chirpIDString := r.PathValue("id")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid Chirp ID")
+		return
+	}
+
+	err = cfg.dbQueries.DeleteChirpLike(r.Context(), database.DeleteChirpLikeParams{
+		UserID:  userID,
+		ChirpID: chirpID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetUserLikes lists the chirps a user liked, most recently liked first.
+// The user must be visible to the viewer.
+func (cfg *apiConfig) handleGetUserLikes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := cfg.pathUserTarget(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	viewerID := cfg.viewerID(r)
+	params := database.ListLikedChirpsParams{
+		UserID:   userID,
+		ViewerID: viewerID,
+		Limit:    limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorLikedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorChirpID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	rows, err := cfg.dbQueries.ListLikedChirps(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	// Likes are paged by when they happened, not by when the chirp was posted.
+	var nextCursor string
+	if len(rows) > int(limit) {
+		rows = rows[:limit]
+		last := rows[len(rows)-1]
+		nextCursor = pagination.EncodeCursor(pagination.Cursor{
+			CreatedAt: last.LikedAt,
+			ID:        last.Chirp.ID,
+		})
+	}
+
+	var dbChirps []database.Chirp
+	for _, row := range rows {
+		dbChirps = append(dbChirps, row.Chirp)
+	}
+
+	dbChirps, err = cfg.filterMutedChirps(r.Context(), viewerID, muteScopeEverywhere, dbChirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	chirps, err := cfg.chirpsForResponse(r.Context(), viewerID, dbChirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, ChirpsPage{
+		Chirps:     chirps,
+		NextCursor: nextCursor,
+	})
+}
@@ -1,15 +1,22 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
+	"github.com/pedroomedicina/chirpy/internal/contentfilter"
 	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/entitlements"
+	"github.com/pedroomedicina/chirpy/internal/media"
+	"github.com/pedroomedicina/chirpy/internal/ratelimit"
+	"github.com/pedroomedicina/chirpy/internal/spam"
 	"log"
 	"net/http"
 	"os"
-	"strings"
+	"strconv"
+	"time"
 )
 
 func respondWithError(w http.ResponseWriter, code int, message string) {
@@ -30,20 +37,46 @@ func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	}
 }
 
-func cleanProfanity(text string) string {
-	profaneWords := []string{"kerfuffle", "sharbert", "fornax"}
-	words := strings.Split(text, " ")
-	for i, word := range words {
-		lowerWord := strings.ToLower(word)
-		for _, profane := range profaneWords {
-			if lowerWord == profane {
-				words[i] = "****"
-				break
-			}
-		}
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid duration for %s: %v", key, err)
+	}
+
+	return duration
+}
+
+func intFromEnv(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		log.Fatalf("Invalid number for %s: %s", key, value)
 	}
 
-	return strings.Join(words, " ")
+	return number
+}
+
+func floatFromEnv(key string, fallback float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number <= 0 {
+		log.Fatalf("Invalid number for %s: %s", key, value)
+	}
+
+	return number
 }
 
 func main() {
@@ -57,13 +90,83 @@ func main() {
 		log.Fatal(err)
 	}
 
+	mediaDir := os.Getenv("MEDIA_DIR")
+	if mediaDir == "" {
+		mediaDir = "media"
+	}
+	mediaStorage, err := media.NewLocalStorage(mediaDir, "/media/")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	plans := entitlements.DefaultPlans()
+	plans.Free.EditWindow = durationFromEnv("CHIRP_EDIT_WINDOW", plans.Free.EditWindow)
+	plans.ChirpyRed.EditWindow = durationFromEnv("CHIRPY_RED_EDIT_WINDOW", plans.ChirpyRed.EditWindow)
+
+	bannedWords := contentfilter.NewWordFilter(nil)
+
+	// Regex rules are optional and run after the banned words, so they see
+	// the masked text.
+	var regexRules []contentfilter.RegexRule
+	if rulesPath := os.Getenv("CONTENT_REGEX_RULES"); rulesPath != "" {
+		rulesFile, err := os.Open(rulesPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		regexRules, err = contentfilter.LoadRegexRules(rulesFile)
+		rulesFile.Close()
+		if err != nil {
+			log.Fatalf("Error loading %s: %v", rulesPath, err)
+		}
+	}
+
+	spamConfig := spam.DefaultConfig()
+	spamConfig.DuplicateWindow = durationFromEnv("SPAM_DUPLICATE_WINDOW", spamConfig.DuplicateWindow)
+	spamConfig.DuplicateSimilarity = floatFromEnv("SPAM_DUPLICATE_SIMILARITY", spamConfig.DuplicateSimilarity)
+	spamConfig.DuplicateWeight = floatFromEnv("SPAM_DUPLICATE_WEIGHT", spamConfig.DuplicateWeight)
+	spamConfig.BurstWindow = durationFromEnv("SPAM_BURST_WINDOW", spamConfig.BurstWindow)
+	spamConfig.BurstLimit = intFromEnv("SPAM_BURST_LIMIT", spamConfig.BurstLimit)
+	spamConfig.BurstWeight = floatFromEnv("SPAM_BURST_WEIGHT", spamConfig.BurstWeight)
+	spamConfig.NewAccountAge = durationFromEnv("SPAM_NEW_ACCOUNT_AGE", spamConfig.NewAccountAge)
+	spamConfig.NewAccountMaxLinks = intFromEnv("SPAM_NEW_ACCOUNT_MAX_LINKS", spamConfig.NewAccountMaxLinks)
+	spamConfig.LinkWeight = floatFromEnv("SPAM_LINK_WEIGHT", spamConfig.LinkWeight)
+	spamConfig.MaxMentions = intFromEnv("SPAM_MAX_MENTIONS", spamConfig.MaxMentions)
+	spamConfig.MentionWeight = floatFromEnv("SPAM_MENTION_WEIGHT", spamConfig.MentionWeight)
+	spamConfig.FlagWeight = floatFromEnv("SPAM_FLAG_WEIGHT", spamConfig.FlagWeight)
+	spamConfig.HoldScore = floatFromEnv("SPAM_HOLD_SCORE", spamConfig.HoldScore)
+	spamConfig.RejectScore = floatFromEnv("SPAM_REJECT_SCORE", spamConfig.RejectScore)
+
 	apiCfg := &apiConfig{
-		db:        db,
-		dbQueries: database.New(db),
-		platform:  os.Getenv("PLATFORM"),
-		jwtSecret: os.Getenv("JWT_SECRET"),
-		polkaKey:  os.Getenv("POLKA_KEY"),
+		db:                   db,
+		dbQueries:            database.New(db),
+		platform:             os.Getenv("PLATFORM"),
+		jwtSecret:            os.Getenv("JWT_SECRET"),
+		polkaKey:             os.Getenv("POLKA_KEY"),
+		adminKey:             os.Getenv("ADMIN_KEY"),
+		trendingWindow:       durationFromEnv("TRENDING_WINDOW", time.Hour),
+		mediaStorage:         mediaStorage,
+		unattachedMediaTTL:   durationFromEnv("MEDIA_UPLOAD_TTL", 24*time.Hour),
+		trashRetention:       durationFromEnv("TRASH_RETENTION", 30*24*time.Hour),
+		handleChangeInterval: durationFromEnv("HANDLE_CHANGE_INTERVAL", 7*24*time.Hour),
+		handleRedirectPeriod: durationFromEnv("HANDLE_REDIRECT_PERIOD", 30*24*time.Hour),
+		plans:                plans,
+		writeLimiter:         ratelimit.New(time.Minute),
+		bannedWords:          bannedWords,
+		contentFilters:       contentfilter.Pipeline{bannedWords, contentfilter.NewRegexFilter(regexRules...)},
+		reportHideThreshold:  intFromEnv("REPORT_HIDE_THRESHOLD", 3),
+		spamConfig:           spamConfig,
 	}
+
+	err = apiCfg.refreshBannedWords(context.Background())
+	if err != nil {
+		log.Printf("Error loading banned words: %v", err)
+	}
+	go apiCfg.refreshBannedWordsPeriodically(context.Background())
+	go runBatchJob(context.Background(), "scheduled chirp publisher", durationFromEnv("CHIRP_PUBLISH_INTERVAL", 10*time.Second), publishBatchSize, apiCfg.publishDueChirps)
+	go runBatchJob(context.Background(), "trash purge", purgeInterval, purgeBatchSize, apiCfg.purgeTrashedChirps)
+	go runBatchJob(context.Background(), "expired chirp reaper", reapInterval, reapBatchSize, apiCfg.reapChirps)
+	go runBatchJob(context.Background(), "unattached media reaper", mediaReapInterval, mediaReapBatchSize, apiCfg.reapUnattachedMedia)
+
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("GET /api/healthz", func(w http.ResponseWriter, r *http.Request) {
@@ -77,25 +180,86 @@ func main() {
 
 	fileServer := http.FileServer(http.Dir("public"))
 	mux.Handle("/app/", apiCfg.middlewareMetricsInc(http.StripPrefix("/app", fileServer)))
+	mux.Handle("/media/", http.StripPrefix("/media", http.FileServer(mediaStorage.FileSystem())))
 	mux.Handle("GET /admin/metrics", http.HandlerFunc(apiCfg.handleMetrics))
 	mux.Handle("POST /admin/reset", http.HandlerFunc(apiCfg.handleReset))
+	mux.Handle("GET /admin/banned-words", http.HandlerFunc(apiCfg.handleListBannedWords))
+	mux.Handle("POST /admin/banned-words", http.HandlerFunc(apiCfg.handleCreateBannedWord))
+	mux.Handle("POST /admin/banned-words/preview", http.HandlerFunc(apiCfg.handlePreviewContentFilters))
+	mux.Handle("PUT /admin/banned-words/{id}", http.HandlerFunc(apiCfg.handleUpdateBannedWord))
+	mux.Handle("DELETE /admin/banned-words/{id}", http.HandlerFunc(apiCfg.handleDeleteBannedWord))
+	mux.Handle("GET /admin/moderation/reports", http.HandlerFunc(apiCfg.handleGetReportedChirps))
+	mux.Handle("GET /admin/moderation/actions", http.HandlerFunc(apiCfg.handleGetModerationActions))
+	mux.Handle("POST /admin/moderation/chirps/{id}/dismiss", http.HandlerFunc(apiCfg.handleDismissReports))
+	mux.Handle("POST /admin/moderation/chirps/{id}/hide", http.HandlerFunc(apiCfg.handleHideChirp))
+	mux.Handle("POST /admin/moderation/chirps/{id}/suspend", http.HandlerFunc(apiCfg.handleSuspendChirpAuthor))
+	mux.Handle("PUT /admin/moderation/users/{id}/status", http.HandlerFunc(apiCfg.handleSetUserStatus))
+	mux.Handle("GET /admin/moderation/appeals", http.HandlerFunc(apiCfg.handleGetAppeals))
+	mux.Handle("POST /admin/moderation/appeals/{id}/approve", http.HandlerFunc(apiCfg.handleApproveAppeal))
+	mux.Handle("POST /admin/moderation/appeals/{id}/deny", http.HandlerFunc(apiCfg.handleDenyAppeal))
+	mux.Handle("GET /admin/spam/config", http.HandlerFunc(apiCfg.handleGetSpamConfig))
+	mux.Handle("GET /admin/audit", http.HandlerFunc(apiCfg.handleGetAuditLog))
 
 	mux.Handle("POST /api/chirps", http.HandlerFunc(apiCfg.handleCreateChirp))
 	mux.Handle("POST /api/users", http.HandlerFunc(apiCfg.handleCreateUser))
 	mux.Handle("PUT /api/users", http.HandlerFunc(apiCfg.handleUpdateUser))
 
 	mux.Handle("GET /api/chirps", http.HandlerFunc(apiCfg.handleGetAllChirps))
+	mux.Handle("GET /api/timeline", http.HandlerFunc(apiCfg.handleGetTimeline))
+	mux.Handle("POST /api/chirps/validate", http.HandlerFunc(apiCfg.handleValidateChirp))
+	mux.Handle("GET /api/chirps/search", http.HandlerFunc(apiCfg.handleSearchChirps))
+	mux.Handle("GET /api/chirps/scheduled", http.HandlerFunc(apiCfg.handleGetScheduledChirps))
 	mux.Handle("GET /api/chirps/{id}", http.HandlerFunc(apiCfg.handleGetChirpByID))
+	mux.Handle("PUT /api/chirps/{id}", http.HandlerFunc(apiCfg.handleUpdateChirp))
 	mux.Handle("DELETE /api/chirps/{id}", http.HandlerFunc(apiCfg.handleDeleteChirp))
+	mux.Handle("GET /api/chirps/{id}/revisions", http.HandlerFunc(apiCfg.handleGetChirpRevisions))
+	mux.Handle("PUT /api/chirps/{id}/schedule", http.HandlerFunc(apiCfg.handleRescheduleChirp))
+	mux.Handle("DELETE /api/chirps/{id}/schedule", http.HandlerFunc(apiCfg.handleCancelScheduledChirp))
+	mux.Handle("POST /api/chirps/{id}/restore", http.HandlerFunc(apiCfg.handleRestoreChirp))
+	mux.Handle("GET /api/chirps/{id}/thread", http.HandlerFunc(apiCfg.handleGetChirpThread))
+	mux.Handle("POST /api/chirps/{id}/rechirp", http.HandlerFunc(apiCfg.handleRechirp))
+	mux.Handle("DELETE /api/chirps/{id}/rechirp", http.HandlerFunc(apiCfg.handleUndoRechirp))
+	mux.Handle("GET /api/chirps/{id}/rechirps", http.HandlerFunc(apiCfg.handleGetRechirps))
+	mux.Handle("GET /api/chirps/{id}/quotes", http.HandlerFunc(apiCfg.handleGetQuoteChirps))
+	mux.Handle("POST /api/chirps/{id}/report", http.HandlerFunc(apiCfg.handleReportChirp))
+	mux.Handle("POST /api/chirps/{id}/like", http.HandlerFunc(apiCfg.handleLikeChirp))
+	mux.Handle("DELETE /api/chirps/{id}/like", http.HandlerFunc(apiCfg.handleUnlikeChirp))
+	mux.Handle("GET /api/users/{handle}", http.HandlerFunc(apiCfg.handleGetUserProfile))
+	mux.Handle("GET /api/users/{handle}/chirps", http.HandlerFunc(apiCfg.handleGetUserChirps))
+	mux.Handle("GET /api/users/{id}/likes", http.HandlerFunc(apiCfg.handleGetUserLikes))
+	mux.Handle("POST /api/users/{id}/block", http.HandlerFunc(apiCfg.handleBlockUser))
+	mux.Handle("DELETE /api/users/{id}/block", http.HandlerFunc(apiCfg.handleUnblockUser))
+	mux.Handle("POST /api/users/{id}/follow", http.HandlerFunc(apiCfg.handleFollowUser))
+	mux.Handle("DELETE /api/users/{id}/follow", http.HandlerFunc(apiCfg.handleUnfollowUser))
+	mux.Handle("GET /api/users/{id}/followers", http.HandlerFunc(apiCfg.handleGetFollowers))
+	mux.Handle("GET /api/users/{id}/following", http.HandlerFunc(apiCfg.handleGetFollowing))
+	mux.Handle("POST /api/users/{id}/mute", http.HandlerFunc(apiCfg.handleMuteUser))
+	mux.Handle("DELETE /api/users/{id}/mute", http.HandlerFunc(apiCfg.handleUnmuteUser))
+	mux.Handle("GET /api/users/me/entitlements", http.HandlerFunc(apiCfg.handleGetEntitlements))
+	mux.Handle("PUT /api/users/me/auto-delete", http.HandlerFunc(apiCfg.handleUpdateAutoDelete))
+	mux.Handle("PUT /api/users/me/handle", http.HandlerFunc(apiCfg.handleUpdateHandle))
+	mux.Handle("GET /api/users/me/trash", http.HandlerFunc(apiCfg.handleGetTrash))
+	mux.Handle("GET /api/users/me/mentions", http.HandlerFunc(apiCfg.handleGetMyMentions))
+	mux.Handle("GET /api/users/me/blocks", http.HandlerFunc(apiCfg.handleGetBlocks))
+	mux.Handle("GET /api/users/me/mutes", http.HandlerFunc(apiCfg.handleGetMutes))
+	mux.Handle("GET /api/users/me/mute-rules", http.HandlerFunc(apiCfg.handleGetMuteRules))
+	mux.Handle("POST /api/users/me/mute-rules", http.HandlerFunc(apiCfg.handleCreateMuteRule))
+	mux.Handle("DELETE /api/users/me/mute-rules/{id}", http.HandlerFunc(apiCfg.handleDeleteMuteRule))
+
+	mux.Handle("POST /api/media", http.HandlerFunc(apiCfg.handleUploadMedia))
+
+	mux.Handle("GET /api/hashtags/trending", http.HandlerFunc(apiCfg.handleGetTrendingHashtags))
+	mux.Handle("GET /api/hashtags/{tag}/chirps", http.HandlerFunc(apiCfg.handleGetHashtagChirps))
 
 	mux.Handle("POST /api/login", http.HandlerFunc(apiCfg.handleLogin))
 	mux.Handle("POST /api/refresh", http.HandlerFunc(apiCfg.handleRefresh))
 	mux.Handle("POST /api/revoke", http.HandlerFunc(apiCfg.handleRevoke))
+	mux.Handle("POST /api/appeals", http.HandlerFunc(apiCfg.handleCreateAppeal))
 	mux.Handle("POST /api/polka/webhooks", http.HandlerFunc(apiCfg.handlePolkaWebHook))
 
 	server := &http.Server{
 		Addr:    ":8080",
-		Handler: mux,
+		Handler: middlewareRequestID(mux),
 	}
 
 	err = server.ListenAndServe()
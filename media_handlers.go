@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/media"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	maxAltTextLength = 1000
+
+	mediaReapInterval  = 10 * time.Minute
+	mediaReapBatchSize = 100
+)
+
+var errInvalidMediaAttachment = errors.New("Media attachment does not exist or is already in use")
+
+func (cfg *apiConfig) handleUploadMedia(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limits, err := cfg.limitsFor(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if !cfg.allowWrite(w, userID, limits) {
+		return
+	}
+
+	// Leave some room on top of the file itself for the multipart framing.
+	r.Body = http.MaxBytesReader(w, r.Body, media.MaxUploadBytes+64<<10)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondWithError(w, http.StatusRequestEntityTooLarge, "File too large")
+			return
+		}
+
+		respondWithError(w, http.StatusBadRequest, "Missing file")
+		return
+	}
+	defer file.Close()
+
+	processed, err := media.Process(file)
+	if err != nil {
+		switch {
+		case errors.Is(err, media.ErrTooLarge):
+			respondWithError(w, http.StatusRequestEntityTooLarge, "File too large")
+		case errors.Is(err, media.ErrUnsupportedType):
+			respondWithError(w, http.StatusUnsupportedMediaType, "Only JPEG, PNG and GIF images are supported")
+		case errors.Is(err, media.ErrInvalidImage):
+			respondWithError(w, http.StatusBadRequest, "Invalid image")
+		default:
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		}
+		return
+	}
+
+	mediaID := uuid.New()
+	storageKey := mediaID.String() + processed.Extension
+	thumbnailKey := mediaID.String() + "_thumb" + processed.ThumbnailExtension
+
+	err = cfg.mediaStorage.Save(r.Context(), storageKey, bytes.NewReader(processed.Data))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	err = cfg.mediaStorage.Save(r.Context(), thumbnailKey, bytes.NewReader(processed.Thumbnail))
+	if err != nil {
+		cfg.removeMediaFiles(r.Context(), []database.MediaAttachment{{StorageKey: storageKey}})
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	dbMedia, err := cfg.dbQueries.CreateMediaAttachment(r.Context(), database.CreateMediaAttachmentParams{
+		ID:           mediaID,
+		UserID:       userID,
+		ContentType:  processed.ContentType,
+		StorageKey:   storageKey,
+		ThumbnailKey: thumbnailKey,
+		Width:        int32(processed.Width),
+		Height:       int32(processed.Height),
+	})
+	if err != nil {
+		cfg.removeMediaFiles(r.Context(), []database.MediaAttachment{{StorageKey: storageKey, ThumbnailKey: thumbnailKey}})
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, cfg.databaseMediaToMedia(dbMedia))
+}
+
+// attachChirpMedia links uploaded media to a new chirp in the order given.
+// Each upload must belong to the author and can only be attached once.
+func attachChirpMedia(ctx context.Context, q *database.Queries, chirpID, userID uuid.UUID, attachments []MediaAttachment) error {
+	for i, attachment := range attachments {
+		attached, err := q.AttachMediaToChirp(ctx, database.AttachMediaToChirpParams{
+			ChirpID:  uuid.NullUUID{UUID: chirpID, Valid: true},
+			Position: int32(i),
+			AltText:  attachment.AltText,
+			ID:       attachment.ID,
+			UserID:   userID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if attached == 0 {
+			return errInvalidMediaAttachment
+		}
+	}
+
+	return nil
+}
+
+// removeMediaFiles deletes the stored files of media whose rows are already
+// gone. Failures are only logged since there is nothing left to roll back.
+func (cfg *apiConfig) removeMediaFiles(ctx context.Context, attachments []database.MediaAttachment) {
+	for _, attachment := range attachments {
+		for _, key := range []string{attachment.StorageKey, attachment.ThumbnailKey} {
+			if key == "" {
+				continue
+			}
+
+			err := cfg.mediaStorage.Delete(ctx, key)
+			if err != nil {
+				log.Printf("Error deleting media file %s: %v", key, err)
+			}
+		}
+	}
+}
+
+// reapUnattachedMedia deletes a batch of uploads that were never attached to a
+// chirp within unattachedMediaTTL, along with their files. Rows are claimed
+// with FOR UPDATE SKIP LOCKED, so an upload being attached right now is left
+// alone.
+func (cfg *apiConfig) reapUnattachedMedia(ctx context.Context) (int, error) {
+	attachments, err := cfg.dbQueries.DeleteUnattachedMedia(ctx, database.DeleteUnattachedMediaParams{
+		TtlSeconds: int32(cfg.unattachedMediaTTL.Seconds()),
+		Limit:      mediaReapBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	cfg.removeMediaFiles(ctx, attachments)
+	return len(attachments), nil
+}
+
+func (cfg *apiConfig) databaseMediaToMedia(dbMedia database.MediaAttachment) MediaAttachment {
+	return MediaAttachment{
+		ID:           dbMedia.ID,
+		URL:          cfg.mediaStorage.URL(dbMedia.StorageKey),
+		ThumbnailURL: cfg.mediaStorage.URL(dbMedia.ThumbnailKey),
+		ContentType:  dbMedia.ContentType,
+		Width:        dbMedia.Width,
+		Height:       dbMedia.Height,
+		AltText:      dbMedia.AltText,
+	}
+}
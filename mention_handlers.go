@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/entities"
+	"github.com/pedroomedicina/chirpy/internal/pagination"
+	"net/http"
+)
+
+// saveChirpMentions replaces the mentions stored for a chirp with the users
+// mentioned in its body. Mentions that do not match a user, or match a user
+// who blocked or was blocked by the author, stay plain text. The handle is
+// stored as written, so the mention survives the user changing it.
+func saveChirpMentions(ctx context.Context, q *database.Queries, dbChirp database.Chirp) error {
+	err := q.DeleteChirpMentions(ctx, dbChirp.ID)
+	if err != nil {
+		return err
+	}
+
+	mentioned := entities.Mentions(dbChirp.Body)
+	if len(mentioned) == 0 {
+		return nil
+	}
+
+	mentionedUsers, err := q.ResolveMentionedUsers(ctx, database.ResolveMentionedUsersParams{
+		Handles:  mentioned,
+		AuthorID: dbChirp.UserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, mentionedUser := range mentionedUsers {
+		err = q.CreateChirpMention(ctx, database.CreateChirpMentionParams{
+			ChirpID: dbChirp.ID,
+			UserID:  mentionedUser.ID,
+			Lower:   mentionedUser.Handle,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cfg *apiConfig) handleGetMyMentions(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	viewerID := uuid.NullUUID{UUID: userID, Valid: true}
+	params := database.ListMentionChirpsParams{
+		UserID:   userID,
+		ViewerID: viewerID,
+		Limit:    limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	dbChirps, err := cfg.dbQueries.ListMentionChirps(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	page, err := cfg.newChirpsPage(r.Context(), viewerID, dbChirps, limit, muteScopeEverywhere)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/pagination"
+	"io"
+	"net/http"
+)
+
+const (
+	moderationAutoHide  = "auto_hide"
+	moderationDismiss   = "dismiss"
+	moderationHide      = "hide"
+	moderationReinstate = "reinstate"
+	moderationShadowban = "shadowban"
+	moderationSpamHold  = "spam_hold"
+	moderationSuspend   = "suspend"
+)
+
+func (cfg *apiConfig) handleGetReportedChirps(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authorizeAdmin(w, r) {
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := database.ListReportedChirpsParams{
+		Limit: limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorFirstReportedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorChirpID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	reported, err := cfg.dbQueries.ListReportedChirps(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	// The queue is ordered oldest report first, so the cursor carries the time
+	// of each chirp's first open report.
+	page := ReportedChirpsPage{
+		ReportedChirps: []ReportedChirp{},
+	}
+	if len(reported) > int(limit) {
+		reported = reported[:limit]
+		last := reported[len(reported)-1]
+		page.NextCursor = pagination.EncodeCursor(pagination.Cursor{
+			CreatedAt: last.FirstReportedAt,
+			ID:        last.ChirpID,
+		})
+	}
+
+	if len(reported) == 0 {
+		respondWithJSON(w, http.StatusOK, page)
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(reported))
+	for _, row := range reported {
+		ids = append(ids, row.ChirpID)
+	}
+
+	dbChirps, err := cfg.dbQueries.GetChirpsByIDs(r.Context(), ids)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	chirps, err := cfg.chirpsForResponse(r.Context(), uuid.NullUUID{}, dbChirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	chirpsByID := make(map[uuid.UUID]Chirp, len(chirps))
+	hiddenByID := make(map[uuid.UUID]bool, len(dbChirps))
+	for i, chirp := range chirps {
+		chirpsByID[chirp.ID] = chirp
+		hiddenByID[chirp.ID] = dbChirps[i].HiddenAt.Valid
+	}
+
+	reasons, err := cfg.dbQueries.GetOpenReportReasons(r.Context(), ids)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	reasonsByID := make(map[uuid.UUID]map[string]int64, len(reported))
+	for _, reason := range reasons {
+		if reasonsByID[reason.ChirpID] == nil {
+			reasonsByID[reason.ChirpID] = make(map[string]int64)
+		}
+		reasonsByID[reason.ChirpID][reason.Reason] = reason.ReportCount
+	}
+
+	for _, row := range reported {
+		page.ReportedChirps = append(page.ReportedChirps, ReportedChirp{
+			Chirp:           chirpsByID[row.ChirpID],
+			ReportCount:     row.ReportCount,
+			Reasons:         reasonsByID[row.ChirpID],
+			FirstReportedAt: row.FirstReportedAt,
+			LastReportedAt:  row.LastReportedAt,
+			Hidden:          hiddenByID[row.ChirpID],
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+func (cfg *apiConfig) handleDismissReports(w http.ResponseWriter, r *http.Request) {
+	cfg.moderateChirp(w, r, moderationDismiss)
+}
+
+func (cfg *apiConfig) handleHideChirp(w http.ResponseWriter, r *http.Request) {
+	cfg.moderateChirp(w, r, moderationHide)
+}
+
+func (cfg *apiConfig) handleSuspendChirpAuthor(w http.ResponseWriter, r *http.Request) {
+	cfg.moderateChirp(w, r, moderationSuspend)
+}
+
+// moderateChirp records a moderator's decision about a chirp, carries it out
+// and closes the chirp's open reports.
+func (cfg *apiConfig) moderateChirp(w http.ResponseWriter, r *http.Request, action string) {
+	if !cfg.authorizeAdmin(w, r) {
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid Chirp ID")
+		return
+	}
+
+	var reqBody struct {
+		Note string `json:"note"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil && !errors.Is(err, io.EOF) {
+		respondWithError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	dbChirp, err := qtx.GetChirpByIDForUpdate(r.Context(), chirpID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+			return
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	reportCount, err := qtx.CountOpenReports(r.Context(), dbChirp.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	authorStatus, err := qtx.GetUserStatus(r.Context(), dbChirp.UserID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	dbAction, err := applyModerationAction(r.Context(), qtx, dbChirp, action, reportCount, reqBody.Note)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	diff := map[string]auditChange{
+		"hidden":       {Old: dbChirp.HiddenAt.Valid, New: action != moderationDismiss},
+		"open_reports": {Old: reportCount, New: 0},
+	}
+	if action == moderationSuspend && authorStatus != userStatusSuspended {
+		diff["author_status"] = auditChange{Old: authorStatus, New: userStatusSuspended}
+	}
+
+	err = recordAudit(r, qtx, auditEntry{
+		actorType:  auditActorAdmin,
+		action:     action,
+		targetType: auditTargetChirp,
+		targetID:   uuid.NullUUID{UUID: dbChirp.ID, Valid: true},
+		diff:       diff,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseModerationActionToModerationAction(dbAction))
+}
+
+// applyModerationAction records a decision about a chirp and carries it out.
+// Automatic hiding leaves the reports open for a moderator to review; every
+// other decision resolves them.
+func applyModerationAction(ctx context.Context, q *database.Queries, dbChirp database.Chirp, action string, reportCount int64, note string) (database.ModerationAction, error) {
+	dbAction, err := q.CreateModerationAction(ctx, database.CreateModerationActionParams{
+		ChirpID:     uuid.NullUUID{UUID: dbChirp.ID, Valid: true},
+		UserID:      uuid.NullUUID{UUID: dbChirp.UserID, Valid: true},
+		Action:      action,
+		ReportCount: int32(reportCount),
+		Note:        note,
+	})
+	if err != nil {
+		return database.ModerationAction{}, err
+	}
+
+	switch action {
+	case moderationDismiss:
+		err = q.UnhideChirp(ctx, dbChirp.ID)
+	case moderationAutoHide, moderationHide, moderationSpamHold:
+		err = q.HideChirp(ctx, dbChirp.ID)
+	case moderationSuspend:
+		err = q.HideChirp(ctx, dbChirp.ID)
+		if err == nil {
+			err = setUserStatus(ctx, q, dbChirp.UserID, userStatusSuspended)
+		}
+	}
+	if err != nil {
+		return database.ModerationAction{}, err
+	}
+
+	if action == moderationAutoHide || action == moderationSpamHold {
+		return dbAction, nil
+	}
+
+	return dbAction, q.ResolveReports(ctx, database.ResolveReportsParams{
+		ChirpID:    dbChirp.ID,
+		ResolvedBy: uuid.NullUUID{UUID: dbAction.ID, Valid: true},
+	})
+}
+
+// setUserStatus changes a user's account status. Suspending a user also
+// revokes their refresh tokens, so they are signed out everywhere.
+func setUserStatus(ctx context.Context, q *database.Queries, userID uuid.UUID, status string) error {
+	err := q.UpdateUserStatus(ctx, database.UpdateUserStatusParams{
+		ID:     userID,
+		Status: status,
+	})
+	if err != nil {
+		return err
+	}
+
+	if status != userStatusSuspended {
+		return nil
+	}
+
+	return q.RevokeUserRefreshTokens(ctx, userID)
+}
+
+func (cfg *apiConfig) handleGetModerationActions(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authorizeAdmin(w, r) {
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := database.ListModerationActionsParams{
+		Limit: limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	dbActions, err := cfg.dbQueries.ListModerationActions(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	page := ModerationActionsPage{
+		Actions: []ModerationAction{},
+	}
+	if len(dbActions) > int(limit) {
+		dbActions = dbActions[:limit]
+		last := dbActions[len(dbActions)-1]
+		page.NextCursor = pagination.EncodeCursor(pagination.Cursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+	}
+
+	for _, dbAction := range dbActions {
+		page.Actions = append(page.Actions, databaseModerationActionToModerationAction(dbAction))
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+func databaseModerationActionToModerationAction(dbAction database.ModerationAction) ModerationAction {
+	action := ModerationAction{
+		ID:          dbAction.ID,
+		CreatedAt:   dbAction.CreatedAt,
+		Action:      dbAction.Action,
+		ReportCount: dbAction.ReportCount,
+		Note:        dbAction.Note,
+	}
+
+	if dbAction.ChirpID.Valid {
+		action.ChirpID = &dbAction.ChirpID.UUID
+	}
+
+	if dbAction.UserID.Valid {
+		action.UserID = &dbAction.UserID.UUID
+	}
+
+	return action
+}
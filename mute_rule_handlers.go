@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/contentfilter"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/entities"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	muteKindKeyword = "keyword"
+	muteKindHashtag = "hashtag"
+
+	// Timeline rules only apply to GET /api/chirps. Everywhere rules apply to
+	// every chirp listing.
+	muteScopeTimeline   = "timeline"
+	muteScopeEverywhere = "everywhere"
+
+	maxMuteRules      = 100
+	maxMuteRuleLength = 100
+)
+
+// muteRules holds the active rules of one viewer, ready to match chirp bodies.
+type muteRules struct {
+	phrases  []contentfilter.Phrase
+	hashtags map[string]bool
+}
+
+// muteRulesFor loads the viewer's unexpired rules that apply in scope.
+func (cfg *apiConfig) muteRulesFor(ctx context.Context, viewerID uuid.NullUUID, scope string) (muteRules, error) {
+	rules := muteRules{hashtags: make(map[string]bool)}
+	if !viewerID.Valid {
+		return rules, nil
+	}
+
+	dbRules, err := cfg.dbQueries.ListMuteRules(ctx, viewerID.UUID)
+	if err != nil {
+		return muteRules{}, err
+	}
+
+	for _, dbRule := range dbRules {
+		if dbRule.Scope == muteScopeTimeline && scope != muteScopeTimeline {
+			continue
+		}
+
+		switch dbRule.Kind {
+		case muteKindHashtag:
+			rules.hashtags[contentfilter.Fold(dbRule.Value)] = true
+		default:
+			rules.phrases = append(rules.phrases, contentfilter.NewPhrase(dbRule.Value))
+		}
+	}
+
+	return rules, nil
+}
+
+func (m muteRules) matches(body string) bool {
+	for _, phrase := range m.phrases {
+		if phrase.MatchString(body) {
+			return true
+		}
+	}
+
+	if len(m.hashtags) > 0 {
+		for _, tag := range entities.Hashtags(body) {
+			if m.hashtags[contentfilter.Fold(tag)] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// filterMutedChirps drops the chirps matching the viewer's mute rules. It runs
+// after a page has been fetched, so a page can hold fewer chirps than the
+// limit while still having a next cursor. The viewer's own chirps are kept.
+func (cfg *apiConfig) filterMutedChirps(ctx context.Context, viewerID uuid.NullUUID, scope string, dbChirps []database.Chirp) ([]database.Chirp, error) {
+	rules, err := cfg.muteRulesFor(ctx, viewerID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rules.phrases) == 0 && len(rules.hashtags) == 0 {
+		return dbChirps, nil
+	}
+
+	var kept []database.Chirp
+	for _, dbChirp := range dbChirps {
+		if dbChirp.UserID != viewerID.UUID && rules.matches(dbChirp.Body) {
+			continue
+		}
+		kept = append(kept, dbChirp)
+	}
+
+	return kept, nil
+}
+
+func (cfg *apiConfig) handleGetMuteRules(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	dbRules, err := cfg.dbQueries.ListMuteRules(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	rules := []MuteRule{}
+	for _, dbRule := range dbRules {
+		rules = append(rules, databaseMuteRuleToMuteRule(dbRule))
+	}
+
+	respondWithJSON(w, http.StatusOK, rules)
+}
+
+func (cfg *apiConfig) handleCreateMuteRule(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	var reqBody struct {
+		Kind      string     `json:"kind"`
+		Value     string     `json:"value"`
+		Scope     string     `json:"scope"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	value := strings.TrimSpace(reqBody.Value)
+	if len(value) > maxMuteRuleLength {
+		respondWithError(w, http.StatusBadRequest, "Mute rule value too long")
+		return
+	}
+
+	switch reqBody.Kind {
+	case muteKindKeyword:
+		if len(contentfilter.NewPhrase(value)) == 0 {
+			respondWithError(w, http.StatusBadRequest, "Keywords must contain at least one word")
+			return
+		}
+	case muteKindHashtag:
+		value = entities.NormalizeHashtag(value)
+		tags := entities.Hashtags("#" + value)
+		if len(tags) != 1 || tags[0] != value {
+			respondWithError(w, http.StatusBadRequest, "Invalid hashtag")
+			return
+		}
+	default:
+		respondWithError(w, http.StatusBadRequest, `Kind must be "keyword" or "hashtag"`)
+		return
+	}
+
+	if reqBody.Scope == "" {
+		reqBody.Scope = muteScopeEverywhere
+	}
+	if reqBody.Scope != muteScopeTimeline && reqBody.Scope != muteScopeEverywhere {
+		respondWithError(w, http.StatusBadRequest, `Scope must be "timeline" or "everywhere"`)
+		return
+	}
+
+	var expiresAt sql.NullTime
+	if reqBody.ExpiresAt != nil {
+		if !reqBody.ExpiresAt.After(time.Now()) {
+			respondWithError(w, http.StatusBadRequest, "expires_at must be in the future")
+			return
+		}
+		expiresAt = sql.NullTime{Time: reqBody.ExpiresAt.UTC(), Valid: true}
+	}
+
+	dbRules, err := cfg.dbQueries.ListMuteRules(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if len(dbRules) >= maxMuteRules {
+		respondWithError(w, http.StatusBadRequest, "Too many mute rules")
+		return
+	}
+
+	dbRule, err := cfg.dbQueries.CreateMuteRule(r.Context(), database.CreateMuteRuleParams{
+		UserID:    userID,
+		Kind:      reqBody.Kind,
+		Value:     value,
+		Scope:     reqBody.Scope,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, databaseMuteRuleToMuteRule(dbRule))
+}
+
+func (cfg *apiConfig) handleDeleteMuteRule(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	ruleID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid Mute Rule ID")
+		return
+	}
+
+	deleted, err := cfg.dbQueries.DeleteMuteRule(r.Context(), database.DeleteMuteRuleParams{
+		ID:     ruleID,
+		UserID: userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func databaseMuteRuleToMuteRule(dbRule database.MuteRule) MuteRule {
+	rule := MuteRule{
+		ID:        dbRule.ID,
+		CreatedAt: dbRule.CreatedAt,
+		Kind:      dbRule.Kind,
+		Value:     dbRule.Value,
+		Scope:     dbRule.Scope,
+	}
+
+	if dbRule.ExpiresAt.Valid {
+		rule.ExpiresAt = &dbRule.ExpiresAt.Time
+	}
+
+	return rule
+}
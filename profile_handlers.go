@@ -0,0 +1,335 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/handles"
+	"github.com/pedroomedicina/chirpy/internal/pagination"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+func (cfg *apiConfig) handleGetUserProfile(w http.ResponseWriter, r *http.Request) {
+	dbUser, ok := cfg.resolveHandle(w, r, "")
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseUserToProfile(dbUser))
+}
+
+// handleGetUserChirps lists a user's chirps, newest first unless sort=asc.
+func (cfg *apiConfig) handleGetUserChirps(w http.ResponseWriter, r *http.Request) {
+	dbUser, ok := cfg.resolveHandle(w, r, "/chirps")
+	if !ok {
+		return
+	}
+
+	validatedSort := "DESC"
+	sortQueryParam := r.URL.Query().Get("sort")
+	if sortQueryParam != "" {
+		var err error
+		validatedSort, err = validateSortDirection(sortQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	viewerID := cfg.viewerID(r)
+	params := database.ListChirpsAscParams{
+		AuthorID: uuid.NullUUID{UUID: dbUser.ID, Valid: true},
+		ViewerID: viewerID,
+		Limit:    limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	dbChirps, err := cfg.listChirps(r.Context(), validatedSort, params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	page, err := cfg.newChirpsPage(r.Context(), viewerID, dbChirps, limit, muteScopeEverywhere)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+// resolveHandle looks up the user named by the handle in the path. A handle
+// the user has recently given up redirects to the same path under their
+// current handle, unless the viewer may not see the user, so the redirect
+// never gives away a hidden user's new handle.
+func (cfg *apiConfig) resolveHandle(w http.ResponseWriter, r *http.Request, suffix string) (database.User, bool) {
+	handle := r.PathValue("handle")
+
+	dbUser, err := cfg.dbQueries.GetUserByHandle(r.Context(), handle)
+	if errors.Is(err, sql.ErrNoRows) {
+		userID, err := cfg.dbQueries.GetHandleRedirect(r.Context(), handle)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+				return database.User{}, false
+			}
+
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return database.User{}, false
+		}
+
+		dbUser, err = cfg.dbQueries.GetUserByID(r.Context(), userID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return database.User{}, false
+		}
+
+		if !cfg.userVisible(w, r, dbUser) {
+			return database.User{}, false
+		}
+
+		location := "/api/users/" + url.PathEscape(dbUser.Handle) + suffix
+		if r.URL.RawQuery != "" {
+			location += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, location, http.StatusFound)
+		return database.User{}, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return database.User{}, false
+	}
+
+	if !cfg.userVisible(w, r, dbUser) {
+		return database.User{}, false
+	}
+
+	return dbUser, true
+}
+
+// userVisible responds with 404 and returns false when the viewer may not see
+// the user's profile: the user is suspended, shadowbanned, or on the other
+// side of a block.
+func (cfg *apiConfig) userVisible(w http.ResponseWriter, r *http.Request, dbUser database.User) bool {
+	if dbUser.Status == userStatusSuspended {
+		respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return false
+	}
+
+	hidden, err := cfg.authorHiddenFrom(r.Context(), cfg.viewerID(r), dbUser.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return false
+	}
+
+	if hidden {
+		respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return false
+	}
+
+	return true
+}
+
+// pathUserTarget resolves the user whose ID is in the path, for endpoints that
+// list their follows or likes. The user must be visible to the viewer.
+func (cfg *apiConfig) pathUserTarget(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid User ID")
+		return uuid.Nil, false
+	}
+
+	dbUser, err := cfg.dbQueries.GetUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+			return uuid.Nil, false
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return uuid.Nil, false
+	}
+
+	if !cfg.userVisible(w, r, dbUser) {
+		return uuid.Nil, false
+	}
+
+	return dbUser.ID, true
+}
+
+// handleUpdateHandle changes the caller's handle. Handles can only be changed
+// once per handleChangeInterval, and the old one keeps redirecting, and stays
+// reserved for the caller, for handleRedirectPeriod.
+func (cfg *apiConfig) handleUpdateHandle(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	var reqBody struct {
+		Handle string `json:"handle"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	handle := handles.Clean(reqBody.Handle)
+	err = handles.Validate(handle)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	// Locking the row makes concurrent changes wait for each other, so only
+	// one of them gets past the handleChangeInterval check.
+	oldUser, err := qtx.GetUserByIDForUpdate(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if handle == oldUser.Handle {
+		respondWithJSON(w, http.StatusOK, databaseUserToProfile(oldUser))
+		return
+	}
+
+	if oldUser.HandleChangedAt.Valid {
+		retryAfter := time.Until(oldUser.HandleChangedAt.Time.Add(cfg.handleChangeInterval))
+		if retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			respondWithError(w, http.StatusTooManyRequests, "Handle was changed too recently")
+			return
+		}
+	}
+
+	taken, err := qtx.IsHandleTaken(r.Context(), database.IsHandleTakenParams{
+		Handle: handle,
+		UserID: userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if taken {
+		respondWithError(w, http.StatusConflict, "Handle is already taken")
+		return
+	}
+
+	// Changing only the case of a handle leaves the old spelling resolving,
+	// so it needs no redirect.
+	if handles.Fold(handle) != handles.Fold(oldUser.Handle) {
+		err = qtx.CreateHandleRedirect(r.Context(), database.CreateHandleRedirectParams{
+			Lower:     oldUser.Handle,
+			UserID:    userID,
+			ExpiresAt: time.Now().UTC().Add(cfg.handleRedirectPeriod),
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+
+		err = qtx.DeleteHandleRedirect(r.Context(), handle)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	dbUser, err := qtx.UpdateUserHandle(r.Context(), database.UpdateUserHandleParams{
+		ID:     userID,
+		Handle: handle,
+	})
+	if err != nil {
+		if isHandleConflict(err) {
+			respondWithError(w, http.StatusConflict, "Handle is already taken")
+			return
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	actorID := uuid.NullUUID{UUID: userID, Valid: true}
+	err = recordAudit(r, qtx, auditEntry{
+		actorType:  auditActorUser,
+		actorID:    actorID,
+		action:     auditActionHandleChange,
+		targetType: auditTargetUser,
+		targetID:   actorID,
+		diff: map[string]auditChange{
+			"handle": {Old: oldUser.Handle, New: dbUser.Handle},
+		},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseUserToProfile(dbUser))
+}
+
+// isHandleConflict reports whether err is the unique violation raised when
+// another user claimed the handle between the IsHandleTaken check and the
+// write.
+func isHandleConflict(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "users_handle_idx"
+}
+
+func databaseUserToProfile(dbUser database.User) Profile {
+	return Profile{
+		ID:          dbUser.ID,
+		Handle:      dbUser.Handle,
+		CreatedAt:   dbUser.CreatedAt,
+		IsChirpyRed: dbUser.IsChirpyRed,
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/pagination"
+	"net/http"
+)
+
+func (cfg *apiConfig) handleRechirp(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	dbChirp, ok := cfg.lookupChirp(w, r)
+	if !ok {
+		return
+	}
+
+	err = cfg.dbQueries.CreateRechirp(r.Context(), database.CreateRechirpParams{
+		UserID:  userID,
+		ChirpID: dbChirp.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handleUndoRechirp(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	chirpIDString := r.PathValue("id")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid Chirp ID")
+		return
+	}
+
+	err = cfg.dbQueries.DeleteRechirp(r.Context(), database.DeleteRechirpParams{
+		UserID:  userID,
+		ChirpID: chirpID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handleGetRechirps(w http.ResponseWriter, r *http.Request) {
+	dbChirp, ok := cfg.lookupChirp(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := database.ListRechirpsParams{
+		ChirpID:  dbChirp.ID,
+		ViewerID: cfg.viewerID(r),
+		Limit:    limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorUserID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	dbRechirps, err := cfg.dbQueries.ListRechirps(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	page := RechirpsPage{
+		Rechirps: []Rechirp{},
+	}
+
+	if len(dbRechirps) > int(limit) {
+		dbRechirps = dbRechirps[:limit]
+		last := dbRechirps[len(dbRechirps)-1]
+		page.NextCursor = pagination.EncodeCursor(pagination.Cursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.UserID,
+		})
+	}
+
+	for _, dbRechirp := range dbRechirps {
+		page.Rechirps = append(page.Rechirps, Rechirp{
+			UserID:    dbRechirp.UserID,
+			ChirpID:   dbRechirp.ChirpID,
+			CreatedAt: dbRechirp.CreatedAt,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+func (cfg *apiConfig) handleGetQuoteChirps(w http.ResponseWriter, r *http.Request) {
+	dbChirp, ok := cfg.lookupChirp(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	viewerID := cfg.viewerID(r)
+	params := database.ListQuoteChirpsParams{
+		QuotedChirpID: uuid.NullUUID{UUID: dbChirp.ID, Valid: true},
+		ViewerID:      viewerID,
+		Limit:         limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	dbQuotes, err := cfg.dbQueries.ListQuoteChirps(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	page, err := cfg.newChirpsPage(r.Context(), viewerID, dbQuotes, limit, muteScopeEverywhere)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
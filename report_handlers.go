@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"net/http"
+	"unicode/utf8"
+)
+
+const maxReportDetailsLength = 1000
+
+var reportReasons = map[string]bool{
+	"spam":           true,
+	"harassment":     true,
+	"hate":           true,
+	"violence":       true,
+	"sexual":         true,
+	"misinformation": true,
+	"other":          true,
+}
+
+func (cfg *apiConfig) handleReportChirp(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	dbChirp, ok := cfg.lookupChirp(w, r)
+	if !ok {
+		return
+	}
+
+	if dbChirp.UserID == userID {
+		respondWithError(w, http.StatusBadRequest, "You cannot report your own chirp")
+		return
+	}
+
+	var reqBody struct {
+		Reason  string `json:"reason"`
+		Details string `json:"details"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	if !reportReasons[reqBody.Reason] {
+		respondWithError(w, http.StatusBadRequest, "Invalid report reason")
+		return
+	}
+
+	if utf8.RuneCountInString(reqBody.Details) > maxReportDetailsLength {
+		respondWithError(w, http.StatusBadRequest, "Report details too long")
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	// Locking the chirp makes concurrent reports take turns, so it is hidden
+	// exactly once when it crosses the threshold.
+	dbChirp, err = qtx.GetChirpByIDForUpdate(r.Context(), dbChirp.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	created, err := qtx.CreateReport(r.Context(), database.CreateReportParams{
+		ChirpID:    dbChirp.ID,
+		ReporterID: uuid.NullUUID{UUID: userID, Valid: true},
+		Reason:     reqBody.Reason,
+		Details:    reqBody.Details,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if created > 0 && !dbChirp.HiddenAt.Valid {
+		reportCount, err := qtx.CountOpenReports(r.Context(), dbChirp.ID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+
+		if reportCount >= int64(cfg.reportHideThreshold) {
+			_, err = applyModerationAction(r.Context(), qtx, dbChirp, moderationAutoHide, reportCount, "")
+			if err != nil {
+				respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+				return
+			}
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/pagination"
+	"net/http"
+	"time"
+)
+
+// publishBatchSize caps how many due chirps one publisher pass claims in a
+// single transaction.
+const publishBatchSize = 100
+
+// publishDueChirps publishes a batch of scheduled chirps that are due. It is
+// safe to run in several server instances at once: each batch is claimed with
+// FOR UPDATE SKIP LOCKED, so every chirp is published by exactly one of them.
+func (cfg *apiConfig) publishDueChirps(ctx context.Context) (int, error) {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	dbChirps, err := qtx.PublishDueChirps(ctx, publishBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, dbChirp := range dbChirps {
+		err = saveChirpEntities(ctx, qtx, dbChirp)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	return len(dbChirps), nil
+}
+
+func (cfg *apiConfig) handleGetScheduledChirps(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := database.ListScheduledChirpsParams{
+		UserID: userID,
+		Limit:  limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorPublishAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	dbChirps, err := cfg.dbQueries.ListScheduledChirps(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	// Scheduled chirps are ordered by when they go out rather than when they
+	// were written, so the cursor carries publish_at.
+	var nextCursor string
+	if len(dbChirps) > int(limit) {
+		dbChirps = dbChirps[:limit]
+		last := dbChirps[len(dbChirps)-1]
+		nextCursor = pagination.EncodeCursor(pagination.Cursor{
+			CreatedAt: last.PublishAt.Time,
+			ID:        last.ID,
+		})
+	}
+
+	chirps, err := cfg.chirpsForResponse(r.Context(), uuid.NullUUID{UUID: userID, Valid: true}, dbChirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, ChirpsPage{
+		Chirps:     chirps,
+		NextCursor: nextCursor,
+	})
+}
+
+func (cfg *apiConfig) handleRescheduleChirp(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	var params struct {
+		PublishAt *time.Time `json:"publish_at"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	if params.PublishAt == nil || !params.PublishAt.After(time.Now()) {
+		respondWithError(w, http.StatusBadRequest, "publish_at must be in the future")
+		return
+	}
+
+	dbChirp, ok := cfg.lookupScheduledChirp(w, r, userID)
+	if !ok {
+		return
+	}
+
+	rescheduledChirp, err := cfg.dbQueries.RescheduleChirp(r.Context(), database.RescheduleChirpParams{
+		ID:        dbChirp.ID,
+		PublishAt: sql.NullTime{Time: params.PublishAt.UTC(), Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusConflict, "Chirp has already been published")
+			return
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	chirps, err := cfg.chirpsForResponse(r.Context(), uuid.NullUUID{UUID: userID, Valid: true}, []database.Chirp{rescheduledChirp})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, chirps[0])
+}
+
+// handleCancelScheduledChirp discards a chirp that has not been published yet.
+func (cfg *apiConfig) handleCancelScheduledChirp(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	dbChirp, ok := cfg.lookupScheduledChirp(w, r, userID)
+	if !ok {
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	// Lock the row so the publisher cannot claim it while it is being removed.
+	lockedChirp, err := qtx.GetChirpByIDForUpdate(r.Context(), dbChirp.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if !lockedChirp.PublishAt.Valid {
+		respondWithError(w, http.StatusConflict, "Chirp has already been published")
+		return
+	}
+
+	attachments, err := qtx.DeleteMediaAttachmentsForChirp(r.Context(), uuid.NullUUID{UUID: dbChirp.ID, Valid: true})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	err = qtx.DeleteChirp(r.Context(), dbChirp.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	cfg.removeMediaFiles(r.Context(), attachments)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// lookupScheduledChirp resolves the {id} path value to one of the user's
+// chirps that is still waiting to be published.
+func (cfg *apiConfig) lookupScheduledChirp(w http.ResponseWriter, r *http.Request, userID uuid.UUID) (database.Chirp, bool) {
+	chirpIDString := r.PathValue("id")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid Chirp ID")
+		return database.Chirp{}, false
+	}
+
+	dbChirp, err := cfg.dbQueries.GetChirpByID(r.Context(), chirpID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+			return database.Chirp{}, false
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return database.Chirp{}, false
+	}
+
+	if dbChirp.UserID != userID {
+		respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return database.Chirp{}, false
+	}
+
+	if !dbChirp.PublishAt.Valid {
+		respondWithError(w, http.StatusConflict, "Chirp has already been published")
+		return database.Chirp{}, false
+	}
+
+	return dbChirp, true
+}
@@ -4,8 +4,10 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"github.com/google/uuid"
 	"github.com/pedroomedicina/chirpy/internal/auth"
 	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/handles"
 	"log"
 	"net/http"
 	"time"
@@ -15,6 +17,7 @@ func (cfg *apiConfig) handleCreateUser(w http.ResponseWriter, r *http.Request) {
 	var reqBody struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		Handle   string `json:"handle"`
 	}
 
 	err := json.NewDecoder(r.Body).Decode(&reqBody)
@@ -23,6 +26,27 @@ func (cfg *apiConfig) handleCreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	handle := handles.Clean(reqBody.Handle)
+	err = handles.Validate(handle)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	taken, err := cfg.dbQueries.IsHandleTaken(r.Context(), database.IsHandleTakenParams{
+		Handle: handle,
+		UserID: uuid.Nil,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if taken {
+		respondWithError(w, http.StatusConflict, "Handle is already taken")
+		return
+	}
+
 	hashedPassword, err := auth.HashPassword(reqBody.Password)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -32,8 +56,14 @@ func (cfg *apiConfig) handleCreateUser(w http.ResponseWriter, r *http.Request) {
 	dbUser, err := cfg.dbQueries.CreateUser(r.Context(), database.CreateUserParams{
 		Email:          reqBody.Email,
 		HashedPassword: hashedPassword,
+		Handle:         handle,
 	})
 
+	if isHandleConflict(err) {
+		respondWithError(w, http.StatusConflict, "Handle is already taken")
+		return
+	}
+
 	if err != nil {
 		log.Printf("Error creating user: %v", err)
 		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -45,6 +75,7 @@ func (cfg *apiConfig) handleCreateUser(w http.ResponseWriter, r *http.Request) {
 		CreatedAt:   dbUser.CreatedAt,
 		UpdatedAt:   dbUser.UpdatedAt,
 		Email:       dbUser.Email,
+		Handle:      dbUser.Handle,
 		IsChirpyRed: dbUser.IsChirpyRed,
 	}
 
@@ -79,6 +110,11 @@ func (cfg *apiConfig) handleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if dbUser.Status == userStatusSuspended {
+		respondWithError(w, http.StatusForbidden, "Account suspended")
+		return
+	}
+
 	accessTokenExpiresIn := time.Hour
 	token, err := auth.MakeJWT(dbUser.ID, cfg.jwtSecret, accessTokenExpiresIn)
 	if err != nil {
@@ -108,10 +144,14 @@ func (cfg *apiConfig) handleLogin(w http.ResponseWriter, r *http.Request) {
 		CreatedAt:    dbUser.CreatedAt,
 		UpdatedAt:    dbUser.UpdatedAt,
 		Email:        dbUser.Email,
+		Handle:       dbUser.Handle,
 		Token:        token,
 		RefreshToken: refreshToken,
 		IsChirpyRed:  dbUser.IsChirpyRed,
 	}
+	if dbUser.AutoDeleteAfterDays.Valid {
+		apiUser.AutoDeleteAfterDays = &dbUser.AutoDeleteAfterDays.Int32
+	}
 
 	respondWithJSON(w, http.StatusOK, apiUser)
 }
@@ -171,9 +211,9 @@ func (cfg *apiConfig) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userID, err := auth.ValidateJWT(tokenString, cfg.jwtSecret)
+	userID, err := cfg.authenticate(r.Context(), tokenString)
 	if err != nil {
-		respondWithError(w, http.StatusUnauthorized, "Invalid or expired token")
+		respondWithAuthError(w, err)
 		return
 	}
 
@@ -193,7 +233,21 @@ func (cfg *apiConfig) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dbUser, err := cfg.dbQueries.UpdateUser(r.Context(), database.UpdateUserParams{
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error updating user")
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	oldUser, err := qtx.GetUserByID(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error updating user")
+		return
+	}
+
+	dbUser, err := qtx.UpdateUser(r.Context(), database.UpdateUserParams{
 		ID:             userID,
 		Email:          reqBody.Email,
 		HashedPassword: hashedPassword,
@@ -203,11 +257,53 @@ func (cfg *apiConfig) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	actorID := uuid.NullUUID{UUID: userID, Valid: true}
+	if oldUser.Email != dbUser.Email {
+		err = recordAudit(r, qtx, auditEntry{
+			actorType:  auditActorUser,
+			actorID:    actorID,
+			action:     auditActionEmailChange,
+			targetType: auditTargetUser,
+			targetID:   actorID,
+			diff: map[string]auditChange{
+				"email": {Old: oldUser.Email, New: dbUser.Email},
+			},
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Error updating user")
+			return
+		}
+	}
+
+	// Password hashes are salted, so the only way to tell whether the
+	// password changed is to check the new one against the old hash. Neither
+	// is written to the audit log.
+	if auth.CheckPasswordHash(reqBody.Password, oldUser.HashedPassword) != nil {
+		err = recordAudit(r, qtx, auditEntry{
+			actorType:  auditActorUser,
+			actorID:    actorID,
+			action:     auditActionPasswordChange,
+			targetType: auditTargetUser,
+			targetID:   actorID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Error updating user")
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error updating user")
+		return
+	}
+
 	apiUser := User{
 		ID:          dbUser.ID,
 		CreatedAt:   dbUser.CreatedAt,
 		UpdatedAt:   dbUser.UpdatedAt,
 		Email:       dbUser.Email,
+		Handle:      dbUser.Handle,
 		IsChirpyRed: dbUser.IsChirpyRed,
 	}
 
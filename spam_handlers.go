@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/spam"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxDuplicateCandidates caps how many of the author's recent chirps a new
+// chirp is compared against.
+const maxDuplicateCandidates = 20
+
+// scoreChirp runs the spam checks on a chirp the user is about to post, or on
+// the new body of a chirp they are editing. The edited chirp is left out of
+// the author's recent activity, so it is not a duplicate of itself.
+func (cfg *apiConfig) scoreChirp(ctx context.Context, userID uuid.UUID, editedChirpID uuid.NullUUID, body string, contentFlags int) (spam.Result, error) {
+	dbUser, err := cfg.dbQueries.GetUserByID(ctx, userID)
+	if err != nil {
+		return spam.Result{}, err
+	}
+
+	recentChirps, err := cfg.dbQueries.CountChirpsSince(ctx, database.CountChirpsSinceParams{
+		UserID:        userID,
+		WindowSeconds: int32(cfg.spamConfig.BurstWindow.Seconds()),
+		ExcludeID:     editedChirpID,
+	})
+	if err != nil {
+		return spam.Result{}, err
+	}
+
+	recentBodies, err := cfg.dbQueries.ListRecentChirpBodies(ctx, database.ListRecentChirpBodiesParams{
+		UserID:        userID,
+		WindowSeconds: int32(cfg.spamConfig.DuplicateWindow.Seconds()),
+		ExcludeID:     editedChirpID,
+		Limit:         maxDuplicateCandidates,
+	})
+	if err != nil {
+		return spam.Result{}, err
+	}
+
+	return cfg.spamConfig.Score(spam.Input{
+		Body:         body,
+		AccountAge:   time.Since(dbUser.CreatedAt),
+		RecentChirps: int(recentChirps),
+		RecentBodies: recentBodies,
+		ContentFlags: contentFlags,
+	}), nil
+}
+
+// holdChirpForSpam hides a new or edited chirp and queues it for moderation with a
+// report that has no reporter. Dismissing the report publishes the chirp.
+func holdChirpForSpam(ctx context.Context, q *database.Queries, dbChirp database.Chirp, result spam.Result) error {
+	note := spamNote(result)
+	_, err := applyModerationAction(ctx, q, dbChirp, moderationSpamHold, 0, note)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateReport(ctx, database.CreateReportParams{
+		ChirpID: dbChirp.ID,
+		Reason:  "spam",
+		Details: note,
+	})
+	return err
+}
+
+// spamNote describes what a chirp scored on, for moderators and logs.
+func spamNote(result spam.Result) string {
+	details := make([]string, 0, len(result.Signals))
+	for _, signal := range result.Signals {
+		details = append(details, fmt.Sprintf("%s +%.1f (%s)", signal.Name, signal.Score, signal.Detail))
+	}
+
+	return fmt.Sprintf("Spam score %.1f: %s", result.Score, strings.Join(details, "; "))
+}
+
+func (cfg *apiConfig) handleGetSpamConfig(w http.ResponseWriter, r *http.Request) {
+	if !cfg.authorizeAdmin(w, r) {
+		return
+	}
+
+	c := cfg.spamConfig
+	respondWithJSON(w, http.StatusOK, SpamConfig{
+		DuplicateWindowSeconds: int(c.DuplicateWindow.Seconds()),
+		DuplicateSimilarity:    c.DuplicateSimilarity,
+		DuplicateWeight:        c.DuplicateWeight,
+		BurstWindowSeconds:     int(c.BurstWindow.Seconds()),
+		BurstLimit:             c.BurstLimit,
+		BurstWeight:            c.BurstWeight,
+		NewAccountAgeSeconds:   int(c.NewAccountAge.Seconds()),
+		NewAccountMaxLinks:     c.NewAccountMaxLinks,
+		LinkWeight:             c.LinkWeight,
+		MaxMentions:            c.MaxMentions,
+		MentionWeight:          c.MentionWeight,
+		FlagWeight:             c.FlagWeight,
+		HoldScore:              c.HoldScore,
+		RejectScore:            c.RejectScore,
+	})
+}
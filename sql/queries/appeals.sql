@@ -0,0 +1,33 @@
+-- name: CreateAppeal :one
+INSERT INTO appeals (id, created_at, user_id, suspended_at, message, status)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    'pending'
+)
+ON CONFLICT (user_id, suspended_at) DO NOTHING
+RETURNING *;
+
+-- name: GetAppealByID :one
+SELECT *
+FROM appeals
+WHERE id = $1;
+
+-- name: ListAppeals :many
+SELECT *
+FROM appeals
+WHERE status = sqlc.arg('status')
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid))
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: DecideAppeal :one
+UPDATE appeals
+SET status = $2, decided_at = NOW(), decision_note = $3
+WHERE id = $1
+  AND status = 'pending'
+RETURNING *;
\ No newline at end of file
@@ -0,0 +1,29 @@
+-- name: CreateAuditLogEntry :exec
+INSERT INTO audit_log (id, created_at, actor_type, actor_id, action, target_type, target_id, request_id, ip, diff)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+);
+
+-- name: ListAuditLog :many
+SELECT *
+FROM audit_log
+WHERE (sqlc.narg('actor_type')::text IS NULL OR actor_type = sqlc.narg('actor_type'))
+  AND (sqlc.narg('actor_id')::uuid IS NULL OR actor_id = sqlc.narg('actor_id'))
+  AND (sqlc.narg('action')::text IS NULL OR action = sqlc.narg('action'))
+  AND (sqlc.narg('target_type')::text IS NULL OR target_type = sqlc.narg('target_type'))
+  AND (sqlc.narg('target_id')::uuid IS NULL OR target_id = sqlc.narg('target_id'))
+  AND (sqlc.narg('since')::timestamp IS NULL OR created_at >= sqlc.narg('since'))
+  AND (sqlc.narg('until')::timestamp IS NULL OR created_at < sqlc.narg('until'))
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
\ No newline at end of file
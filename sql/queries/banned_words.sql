@@ -0,0 +1,35 @@
+-- name: CreateBannedWord :one
+INSERT INTO banned_words (id, created_at, updated_at, word, severity)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    NOW(),
+    $1,
+    $2
+)
+ON CONFLICT (word) DO NOTHING
+RETURNING *;
+
+-- name: ListBannedWords :many
+SELECT *
+FROM banned_words
+ORDER BY word ASC;
+
+-- name: LockBannedWords :exec
+LOCK TABLE banned_words IN SHARE ROW EXCLUSIVE MODE;
+
+-- name: GetBannedWordByIDForUpdate :one
+SELECT *
+FROM banned_words
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateBannedWordSeverity :one
+UPDATE banned_words
+SET severity = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteBannedWord :execrows
+DELETE FROM banned_words
+WHERE id = $1;
\ No newline at end of file
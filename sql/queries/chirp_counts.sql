@@ -0,0 +1,36 @@
+-- name: GetChirpCounts :many
+SELECT
+    chirps.id,
+    (
+        SELECT COUNT(*)
+        FROM rechirps
+        WHERE rechirps.chirp_id = chirps.id
+          AND rechirps.user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.narg('viewer_id')::uuid)
+          AND rechirps.user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid))
+    ) AS rechirp_count,
+    (
+        SELECT COUNT(*)
+        FROM chirps AS quotes
+        WHERE quotes.quoted_chirp_id = chirps.id
+          AND quotes.tombstoned_at IS NULL
+          AND quotes.publish_at IS NULL
+          AND quotes.deleted_at IS NULL
+          AND quotes.hidden_at IS NULL
+          AND (quotes.expires_at IS NULL OR quotes.expires_at > NOW())
+          AND quotes.user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.narg('viewer_id')::uuid)
+          AND quotes.user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid))
+    ) AS quote_count,
+    (
+        SELECT COUNT(*)
+        FROM chirp_likes
+        WHERE chirp_likes.chirp_id = chirps.id
+          AND chirp_likes.user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.narg('viewer_id')::uuid)
+          AND chirp_likes.user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid))
+    ) AS like_count,
+    EXISTS (
+        SELECT 1
+        FROM chirp_likes
+        WHERE chirp_likes.chirp_id = chirps.id AND chirp_likes.user_id = sqlc.narg('viewer_id')::uuid
+    ) AS liked_by_me
+FROM chirps
+WHERE chirps.id = ANY(sqlc.arg('ids')::uuid[]);
\ No newline at end of file
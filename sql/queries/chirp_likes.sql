@@ -0,0 +1,24 @@
+-- name: CreateChirpLike :exec
+INSERT INTO chirp_likes (user_id, chirp_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (user_id, chirp_id) DO NOTHING;
+
+-- name: DeleteChirpLike :exec
+DELETE FROM chirp_likes
+WHERE user_id = $1 AND chirp_id = $2;
+
+-- name: ListLikedChirps :many
+SELECT sqlc.embed(chirps), chirp_likes.created_at AS liked_at
+FROM chirp_likes
+INNER JOIN chirps ON chirps.id = chirp_likes.chirp_id
+WHERE chirp_likes.user_id = sqlc.arg('user_id')
+  AND chirps.tombstoned_at IS NULL
+  AND chirps.deleted_at IS NULL
+  AND chirps.hidden_at IS NULL
+  AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+  AND (sqlc.narg('cursor_liked_at')::timestamp IS NULL
+    OR (chirp_likes.created_at, chirp_likes.chirp_id) < (sqlc.narg('cursor_liked_at')::timestamp, sqlc.narg('cursor_chirp_id')::uuid))
+  AND chirps.user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.narg('viewer_id')::uuid)
+  AND chirps.user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid))
+ORDER BY chirp_likes.created_at DESC, chirp_likes.chirp_id DESC
+LIMIT sqlc.arg('limit');
\ No newline at end of file
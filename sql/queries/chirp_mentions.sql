@@ -0,0 +1,40 @@
+-- name: ResolveMentionedUsers :many
+SELECT id, handle
+FROM users
+WHERE lower(handle) = ANY(sqlc.arg('handles')::text[])
+  AND NOT EXISTS (
+    SELECT 1
+    FROM user_blocks
+    WHERE (blocker_id = users.id AND blocked_id = sqlc.arg('author_id'))
+       OR (blocker_id = sqlc.arg('author_id') AND blocked_id = users.id)
+  );
+
+-- name: CreateChirpMention :exec
+INSERT INTO chirp_mentions (chirp_id, user_id, created_at, handle)
+VALUES ($1, $2, NOW(), lower($3))
+ON CONFLICT (chirp_id, user_id) DO NOTHING;
+
+-- name: DeleteChirpMentions :exec
+DELETE FROM chirp_mentions
+WHERE chirp_id = $1;
+
+-- name: GetMentionedUsersForChirps :many
+SELECT chirp_id, user_id, handle
+FROM chirp_mentions
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[]);
+
+-- name: ListMentionChirps :many
+SELECT chirps.*
+FROM chirps
+INNER JOIN chirp_mentions ON chirp_mentions.chirp_id = chirps.id
+WHERE chirp_mentions.user_id = sqlc.arg('user_id')
+  AND chirps.tombstoned_at IS NULL
+  AND chirps.deleted_at IS NULL
+  AND chirps.hidden_at IS NULL
+  AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (chirps.created_at, chirps.id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid))
+  AND chirps.user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.narg('viewer_id')::uuid)
+  AND chirps.user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid))
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg('limit');
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- name: CreateChirpRevision :one
+INSERT INTO chirp_revisions (id, created_at, chirp_id, body)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    $1,
+    $2
+)
+RETURNING *;
+
+-- name: GetChirpRevisions :many
+SELECT *
+FROM chirp_revisions
+WHERE chirp_id = $1
+ORDER BY created_at DESC;
+
+-- name: DeleteChirpRevisions :exec
+DELETE FROM chirp_revisions
+WHERE chirp_id = $1;
\ No newline at end of file
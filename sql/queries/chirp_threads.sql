@@ -0,0 +1,35 @@
+-- name: GetChirpAncestors :many
+WITH RECURSIVE ancestors AS (
+    SELECT chirps.id, chirps.parent_chirp_id
+    FROM chirps
+    WHERE chirps.id = $1
+    UNION ALL
+    SELECT parents.id, parents.parent_chirp_id
+    FROM chirps AS parents
+    INNER JOIN ancestors ON parents.id = ancestors.parent_chirp_id
+)
+SELECT *
+FROM chirps
+WHERE id IN (SELECT parent_chirp_id FROM ancestors)
+ORDER BY created_at ASC, id ASC;
+
+-- name: GetChirpReplies :many
+WITH RECURSIVE replies AS (
+    SELECT chirps.id
+    FROM chirps
+    WHERE chirps.parent_chirp_id = $1
+    UNION ALL
+    SELECT children.id
+    FROM chirps AS children
+    INNER JOIN replies ON children.parent_chirp_id = replies.id
+)
+SELECT *
+FROM chirps
+WHERE id IN (SELECT id FROM replies)
+  AND publish_at IS NULL
+ORDER BY created_at ASC, id ASC;
+
+-- name: CountChirpReplies :one
+SELECT COUNT(*)
+FROM chirps
+WHERE parent_chirp_id = $1;
\ No newline at end of file
@@ -1,11 +1,15 @@
 -- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, parent_chirp_id, quoted_chirp_id, publish_at, expires_at)
 VALUES (
     gen_random_uuid(),
     NOW(),
     NOW(),
     $1,
-    $2
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
 )
 RETURNING *;
 
@@ -18,11 +22,278 @@ SELECT *
 FROM chirps
 WHERE id = $1;
 
+-- name: GetChirpsByIDs :many
+SELECT *
+FROM chirps
+WHERE id = ANY(sqlc.arg('ids')::uuid[]);
+
+-- name: GetChirpByIDForUpdate :one
+SELECT *
+FROM chirps
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateChirpBody :one
+UPDATE chirps
+SET body = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: TombstoneChirp :exec
+UPDATE chirps
+SET body = '', tombstoned_at = NOW(), updated_at = NOW()
+WHERE id = $1;
+
+-- name: SoftDeleteChirp :exec
+UPDATE chirps
+SET deleted_at = NOW()
+WHERE id = $1;
+
+-- name: RestoreChirp :one
+UPDATE chirps
+SET deleted_at = NULL
+WHERE id = $1
+  AND deleted_at IS NOT NULL
+  AND tombstoned_at IS NULL
+RETURNING *;
+
+-- name: HideChirp :exec
+UPDATE chirps
+SET hidden_at = COALESCE(hidden_at, NOW())
+WHERE id = $1;
+
+-- name: UnhideChirp :exec
+UPDATE chirps
+SET hidden_at = NULL
+WHERE id = $1;
+
 -- name: DeleteChirp :exec
 DELETE FROM chirps
 WHERE id = $1;
 
+-- name: CountRecentChirps :one
+SELECT COUNT(*)
+FROM chirps
+WHERE user_id = $1
+  AND created_at > NOW() - INTERVAL '1 day';
+
+-- name: CountChirpsSince :one
+SELECT COUNT(*)
+FROM chirps
+WHERE user_id = sqlc.arg('user_id')
+  AND created_at > NOW() - sqlc.arg('window_seconds')::integer * INTERVAL '1 second'
+  AND id IS DISTINCT FROM sqlc.narg('exclude_id')::uuid;
+
+-- name: ListRecentChirpBodies :many
+SELECT body
+FROM chirps
+WHERE user_id = sqlc.arg('user_id')
+  AND created_at > NOW() - sqlc.arg('window_seconds')::integer * INTERVAL '1 second'
+  AND tombstoned_at IS NULL
+  AND id IS DISTINCT FROM sqlc.narg('exclude_id')::uuid
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit');
+
 -- name: GetChirpsByUserId :many
 SELECT *
 FROM chirps
-WHERE user_id = $1;
\ No newline at end of file
+WHERE user_id = $1;
+
+-- name: ListChirpsAsc :many
+SELECT *
+FROM chirps
+WHERE tombstoned_at IS NULL
+  AND publish_at IS NULL
+  AND deleted_at IS NULL
+  AND hidden_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id'))
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid))
+  AND user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.narg('viewer_id')::uuid)
+  AND user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid))
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: ListChirpsDesc :many
+SELECT *
+FROM chirps
+WHERE tombstoned_at IS NULL
+  AND publish_at IS NULL
+  AND deleted_at IS NULL
+  AND hidden_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id'))
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid))
+  AND user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.narg('viewer_id')::uuid)
+  AND user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListTimeline :many
+SELECT *
+FROM chirps
+WHERE user_id IN (
+    SELECT followed_id FROM follows WHERE follower_id = sqlc.arg('viewer_id')
+    UNION ALL
+    SELECT sqlc.arg('viewer_id')::uuid
+)
+  AND tombstoned_at IS NULL
+  AND publish_at IS NULL
+  AND deleted_at IS NULL
+  AND hidden_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid))
+  AND user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.arg('viewer_id'))
+  AND user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id <> sqlc.arg('viewer_id')))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: SearchChirpsByRank :many
+SELECT id, created_at, updated_at, body, user_id, parent_chirp_id, quoted_chirp_id, expires_at, rank
+FROM (
+    SELECT id, created_at, updated_at, body, user_id, parent_chirp_id, quoted_chirp_id, expires_at,
+        ts_rank(search_vector, websearch_to_tsquery('english', sqlc.arg('query'))) AS rank
+    FROM chirps
+    WHERE search_vector @@ websearch_to_tsquery('english', sqlc.arg('query'))
+      AND tombstoned_at IS NULL
+      AND publish_at IS NULL
+      AND deleted_at IS NULL
+      AND hidden_at IS NULL
+      AND (expires_at IS NULL OR expires_at > NOW())
+      AND (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id'))
+) AS ranked
+WHERE (sqlc.narg('cursor_rank')::real IS NULL
+    OR (rank, created_at, id) < (sqlc.narg('cursor_rank')::real, sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid))
+  AND user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.narg('viewer_id')::uuid)
+  AND user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid))
+ORDER BY rank DESC, created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: SearchChirpsAsc :many
+SELECT *
+FROM chirps
+WHERE search_vector @@ websearch_to_tsquery('english', sqlc.arg('query'))
+  AND tombstoned_at IS NULL
+  AND publish_at IS NULL
+  AND deleted_at IS NULL
+  AND hidden_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id'))
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid))
+  AND user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.narg('viewer_id')::uuid)
+  AND user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid))
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: SearchChirpsDesc :many
+SELECT *
+FROM chirps
+WHERE search_vector @@ websearch_to_tsquery('english', sqlc.arg('query'))
+  AND tombstoned_at IS NULL
+  AND publish_at IS NULL
+  AND deleted_at IS NULL
+  AND hidden_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id'))
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid))
+  AND user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.narg('viewer_id')::uuid)
+  AND user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListQuoteChirps :many
+SELECT *
+FROM chirps
+WHERE quoted_chirp_id = sqlc.arg('quoted_chirp_id')
+  AND tombstoned_at IS NULL
+  AND publish_at IS NULL
+  AND deleted_at IS NULL
+  AND hidden_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid))
+  AND user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.narg('viewer_id')::uuid)
+  AND user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListScheduledChirps :many
+SELECT *
+FROM chirps
+WHERE user_id = sqlc.arg('user_id')
+  AND publish_at IS NOT NULL
+  AND (sqlc.narg('cursor_publish_at')::timestamp IS NULL
+    OR (publish_at, id) > (sqlc.narg('cursor_publish_at')::timestamp, sqlc.narg('cursor_id')::uuid))
+ORDER BY publish_at ASC, id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: RescheduleChirp :one
+UPDATE chirps
+SET publish_at = $2, updated_at = NOW()
+WHERE id = $1
+  AND publish_at IS NOT NULL
+RETURNING *;
+
+-- name: PublishDueChirps :many
+UPDATE chirps
+SET publish_at = NULL, created_at = NOW(), updated_at = NOW()
+WHERE id IN (
+    SELECT id
+    FROM chirps AS due
+    WHERE due.publish_at <= NOW()
+      AND due.deleted_at IS NULL
+      AND (due.expires_at IS NULL OR due.expires_at > NOW())
+    ORDER BY due.publish_at ASC
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: ListTrashedChirps :many
+SELECT *
+FROM chirps
+WHERE user_id = sqlc.arg('user_id')
+  AND deleted_at IS NOT NULL
+  AND tombstoned_at IS NULL
+  AND hidden_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND (sqlc.narg('cursor_deleted_at')::timestamp IS NULL
+    OR (deleted_at, id) < (sqlc.narg('cursor_deleted_at')::timestamp, sqlc.narg('cursor_id')::uuid))
+ORDER BY deleted_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListPurgeableChirps :many
+SELECT id
+FROM chirps
+WHERE deleted_at < NOW() - sqlc.arg('retention_seconds')::integer * INTERVAL '1 second'
+  AND tombstoned_at IS NULL
+ORDER BY deleted_at ASC
+LIMIT sqlc.arg('limit')
+FOR UPDATE SKIP LOCKED;
+
+-- name: ListExpiredChirps :many
+SELECT id
+FROM chirps
+WHERE expires_at <= NOW()
+  AND tombstoned_at IS NULL
+ORDER BY expires_at ASC
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: ListAutoDeletedChirps :many
+SELECT chirps.id
+FROM users
+INNER JOIN chirps ON chirps.user_id = users.id
+WHERE users.auto_delete_after_days IS NOT NULL
+  AND chirps.created_at < NOW() - users.auto_delete_after_days * INTERVAL '1 day'
+  AND chirps.publish_at IS NULL
+  AND chirps.tombstoned_at IS NULL
+  AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+ORDER BY chirps.created_at ASC
+LIMIT $1
+FOR UPDATE OF chirps SKIP LOCKED;
\ No newline at end of file
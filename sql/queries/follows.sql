@@ -0,0 +1,40 @@
+-- name: CreateFollow :execrows
+INSERT INTO follows (follower_id, followed_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (follower_id, followed_id) DO NOTHING;
+
+-- name: DeleteFollow :execrows
+DELETE FROM follows
+WHERE follower_id = $1
+  AND followed_id = $2;
+
+-- name: DeleteFollowsBetween :exec
+DELETE FROM follows
+WHERE (follower_id = sqlc.arg('user_id') AND followed_id = sqlc.arg('other_user_id'))
+   OR (follower_id = sqlc.arg('other_user_id') AND followed_id = sqlc.arg('user_id'));
+
+-- name: ListFollowers :many
+SELECT follows.follower_id AS user_id, users.handle, follows.created_at
+FROM follows
+INNER JOIN users ON users.id = follows.follower_id
+WHERE follows.followed_id = sqlc.arg('user_id')
+  AND users.status <> 'suspended'
+  AND (users.status <> 'shadowbanned' OR users.id = sqlc.narg('viewer_id')::uuid)
+  AND users.id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.narg('viewer_id')::uuid)
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (follows.created_at, follows.follower_id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_user_id')::uuid))
+ORDER BY follows.created_at DESC, follows.follower_id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListFollowing :many
+SELECT follows.followed_id AS user_id, users.handle, follows.created_at
+FROM follows
+INNER JOIN users ON users.id = follows.followed_id
+WHERE follows.follower_id = sqlc.arg('user_id')
+  AND users.status <> 'suspended'
+  AND (users.status <> 'shadowbanned' OR users.id = sqlc.narg('viewer_id')::uuid)
+  AND users.id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.narg('viewer_id')::uuid)
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (follows.created_at, follows.followed_id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_user_id')::uuid))
+ORDER BY follows.created_at DESC, follows.followed_id DESC
+LIMIT sqlc.arg('limit');
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- name: GetHandleRedirect :one
+SELECT user_id
+FROM handle_redirects
+WHERE handle = lower($1)
+  AND expires_at > NOW();
+
+-- name: CreateHandleRedirect :exec
+INSERT INTO handle_redirects (handle, user_id, created_at, expires_at)
+VALUES (lower($1), $2, NOW(), $3)
+ON CONFLICT (handle) DO UPDATE
+SET user_id = EXCLUDED.user_id, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at;
+
+-- name: DeleteHandleRedirect :exec
+DELETE FROM handle_redirects
+WHERE handle = lower($1);
\ No newline at end of file
@@ -0,0 +1,61 @@
+-- name: UpsertHashtag :one
+INSERT INTO hashtags (id, created_at, tag)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    $1
+)
+ON CONFLICT (tag) DO UPDATE SET tag = EXCLUDED.tag
+RETURNING *;
+
+-- name: CreateChirpHashtag :exec
+INSERT INTO chirp_hashtags (chirp_id, hashtag_id, created_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (chirp_id, hashtag_id) DO NOTHING;
+
+-- name: DeleteChirpHashtags :exec
+DELETE FROM chirp_hashtags
+WHERE chirp_id = $1;
+
+-- name: ListHashtagChirps :many
+SELECT chirps.*
+FROM chirps
+INNER JOIN chirp_hashtags ON chirp_hashtags.chirp_id = chirps.id
+INNER JOIN hashtags ON hashtags.id = chirp_hashtags.hashtag_id
+WHERE hashtags.tag = sqlc.arg('tag')
+  AND chirps.tombstoned_at IS NULL
+  AND chirps.deleted_at IS NULL
+  AND chirps.hidden_at IS NULL
+  AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (chirps.created_at, chirps.id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid))
+  AND chirps.user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.narg('viewer_id')::uuid)
+  AND chirps.user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid))
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetTrendingHashtags :many
+WITH usage AS (
+    SELECT
+        chirp_hashtags.hashtag_id,
+        COUNT(*) FILTER (WHERE chirp_hashtags.created_at >= NOW() - sqlc.arg('window_seconds')::integer * INTERVAL '1 second') AS recent_count,
+        COUNT(*) FILTER (WHERE chirp_hashtags.created_at < NOW() - sqlc.arg('window_seconds')::integer * INTERVAL '1 second') AS previous_count
+    FROM chirp_hashtags
+    INNER JOIN chirps ON chirps.id = chirp_hashtags.chirp_id
+    WHERE chirp_hashtags.created_at >= NOW() - 2 * sqlc.arg('window_seconds')::integer * INTERVAL '1 second'
+      AND chirps.deleted_at IS NULL
+      AND chirps.hidden_at IS NULL
+      AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+      AND chirps.user_id NOT IN (SELECT id FROM users WHERE status IN ('shadowbanned', 'suspended'))
+    GROUP BY chirp_hashtags.hashtag_id
+)
+SELECT
+    hashtags.tag,
+    usage.recent_count,
+    usage.previous_count,
+    (usage.recent_count - usage.previous_count)::float8 / (usage.previous_count + 1) AS growth
+FROM usage
+INNER JOIN hashtags ON hashtags.id = usage.hashtag_id
+WHERE usage.recent_count >= sqlc.arg('min_count')::bigint
+ORDER BY growth DESC, usage.recent_count DESC, hashtags.tag ASC
+LIMIT sqlc.arg('limit');
\ No newline at end of file
@@ -0,0 +1,32 @@
+-- name: CreateMediaAttachment :one
+INSERT INTO media_attachments (id, created_at, user_id, content_type, storage_key, thumbnail_key, width, height)
+VALUES ($1, NOW(), $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: AttachMediaToChirp :execrows
+UPDATE media_attachments
+SET chirp_id = $1, position = $2, alt_text = $3
+WHERE id = $4 AND user_id = $5 AND chirp_id IS NULL;
+
+-- name: GetMediaAttachmentsForChirps :many
+SELECT * FROM media_attachments
+WHERE chirp_id = ANY(sqlc.arg(chirp_ids)::uuid[])
+ORDER BY chirp_id, position;
+
+-- name: DeleteMediaAttachmentsForChirp :many
+DELETE FROM media_attachments
+WHERE chirp_id = $1
+RETURNING *;
+
+-- name: DeleteUnattachedMedia :many
+DELETE FROM media_attachments
+WHERE id IN (
+    SELECT id
+    FROM media_attachments
+    WHERE chirp_id IS NULL
+      AND created_at < NOW() - sqlc.arg('ttl_seconds')::integer * INTERVAL '1 second'
+    ORDER BY created_at
+    LIMIT sqlc.arg('limit')
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- name: CreateModerationAction :one
+INSERT INTO moderation_actions (id, created_at, chirp_id, user_id, action, report_count, note)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+RETURNING *;
+
+-- name: ListModerationActions :many
+SELECT *
+FROM moderation_actions
+WHERE sqlc.narg('cursor_created_at')::timestamp IS NULL
+   OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
\ No newline at end of file
@@ -0,0 +1,24 @@
+-- name: CreateMuteRule :one
+INSERT INTO mute_rules (id, created_at, user_id, kind, value, scope, expires_at)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+RETURNING *;
+
+-- name: DeleteMuteRule :execrows
+DELETE FROM mute_rules
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: ListMuteRules :many
+SELECT *
+FROM mute_rules
+WHERE user_id = $1
+  AND (expires_at IS NULL OR expires_at > NOW())
+ORDER BY created_at DESC, id DESC;
\ No newline at end of file
@@ -0,0 +1,23 @@
+-- name: CreateRechirp :exec
+INSERT INTO rechirps (user_id, chirp_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (user_id, chirp_id) DO NOTHING;
+
+-- name: DeleteRechirp :exec
+DELETE FROM rechirps
+WHERE user_id = $1 AND chirp_id = $2;
+
+-- name: DeleteRechirpsForChirp :exec
+DELETE FROM rechirps
+WHERE chirp_id = $1;
+
+-- name: ListRechirps :many
+SELECT *
+FROM rechirps
+WHERE chirp_id = sqlc.arg('chirp_id')
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, user_id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_user_id')::uuid))
+  AND user_id NOT IN (SELECT author_id FROM viewer_hidden_authors WHERE viewer_id = sqlc.narg('viewer_id')::uuid)
+  AND user_id NOT IN (SELECT id FROM users WHERE status = 'suspended' OR (status = 'shadowbanned' AND id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid))
+ORDER BY created_at DESC, user_id DESC
+LIMIT sqlc.arg('limit');
\ No newline at end of file
@@ -16,11 +16,19 @@ FROM
 WHERE
     refresh_tokens.token = $1
   AND refresh_tokens.expires_at > NOW()
-  AND refresh_tokens.revoked_at IS NULL;
+  AND refresh_tokens.revoked_at IS NULL
+  AND users.status <> 'suspended';
 
 -- name: RevokeRefreshToken :exec
 UPDATE refresh_tokens
 SET
     revoked_at = NOW(),
     updated_at = NOW()
-WHERE token = $1 AND revoked_at IS NULL;
\ No newline at end of file
+WHERE token = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserRefreshTokens :exec
+UPDATE refresh_tokens
+SET
+    revoked_at = NOW(),
+    updated_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL;
\ No newline at end of file
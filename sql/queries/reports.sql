@@ -0,0 +1,45 @@
+-- name: CreateReport :execrows
+INSERT INTO reports (id, created_at, chirp_id, reporter_id, reason, details)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    $4
+)
+ON CONFLICT (chirp_id, reporter_id) WHERE resolved_at IS NULL DO NOTHING;
+
+-- name: CountOpenReports :one
+SELECT COUNT(*)
+FROM reports
+WHERE chirp_id = $1
+  AND resolved_at IS NULL;
+
+-- name: ListReportedChirps :many
+SELECT
+    chirp_id,
+    COUNT(*) AS report_count,
+    MIN(created_at)::timestamp AS first_reported_at,
+    MAX(created_at)::timestamp AS last_reported_at
+FROM reports
+WHERE resolved_at IS NULL
+GROUP BY chirp_id
+HAVING sqlc.narg('cursor_first_reported_at')::timestamp IS NULL
+    OR (MIN(created_at), chirp_id) > (sqlc.narg('cursor_first_reported_at')::timestamp, sqlc.narg('cursor_chirp_id')::uuid)
+ORDER BY first_reported_at ASC, chirp_id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: GetOpenReportReasons :many
+SELECT chirp_id, reason, COUNT(*) AS report_count
+FROM reports
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[])
+  AND resolved_at IS NULL
+GROUP BY chirp_id, reason
+ORDER BY chirp_id, report_count DESC, reason;
+
+-- name: ResolveReports :exec
+UPDATE reports
+SET resolved_at = NOW(), resolved_by = $2
+WHERE chirp_id = $1
+  AND resolved_at IS NULL;
\ No newline at end of file
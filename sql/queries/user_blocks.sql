@@ -0,0 +1,31 @@
+-- name: CreateUserBlock :execrows
+INSERT INTO user_blocks (blocker_id, blocked_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (blocker_id, blocked_id) DO NOTHING;
+
+-- name: DeleteUserBlock :execrows
+DELETE FROM user_blocks
+WHERE blocker_id = $1
+  AND blocked_id = $2;
+
+-- name: GetHiddenAuthors :many
+SELECT author_id
+FROM viewer_hidden_authors
+WHERE viewer_id = $1;
+
+-- name: HasBlockBetween :one
+SELECT EXISTS (
+    SELECT 1
+    FROM user_blocks
+    WHERE (blocker_id = sqlc.arg('user_id') AND blocked_id = sqlc.arg('other_user_id'))
+       OR (blocker_id = sqlc.arg('other_user_id') AND blocked_id = sqlc.arg('user_id'))
+);
+
+-- name: ListUserBlocks :many
+SELECT *
+FROM user_blocks
+WHERE blocker_id = sqlc.arg('blocker_id')
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, blocked_id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_blocked_id')::uuid))
+ORDER BY created_at DESC, blocked_id DESC
+LIMIT sqlc.arg('limit');
\ No newline at end of file
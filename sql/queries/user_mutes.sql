@@ -0,0 +1,18 @@
+-- name: CreateUserMute :execrows
+INSERT INTO user_mutes (muter_id, muted_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (muter_id, muted_id) DO NOTHING;
+
+-- name: DeleteUserMute :execrows
+DELETE FROM user_mutes
+WHERE muter_id = $1
+  AND muted_id = $2;
+
+-- name: ListUserMutes :many
+SELECT *
+FROM user_mutes
+WHERE muter_id = sqlc.arg('muter_id')
+  AND (sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, muted_id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_muted_id')::uuid))
+ORDER BY created_at DESC, muted_id DESC
+LIMIT sqlc.arg('limit');
\ No newline at end of file
@@ -1,29 +1,90 @@
 -- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, email, hashed_password)
+INSERT INTO users (id, created_at, updated_at, email, hashed_password, handle)
 VALUES (
            gen_random_uuid(),
             NOW(),
             NOW(),
             $1,
-            $2
+            $2,
+            $3
        )
 RETURNING *;
 
--- name: DeleteAllUsers :exec
+-- name: DeleteAllUsers :execrows
 DELETE FROM users;
 
 -- name: GetUserByEmail :one
-SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, auto_delete_after_days, status, status_changed_at, handle, handle_changed_at
 FROM users
 WHERE email = $1;
 
+-- name: GetUserByHandle :one
+SELECT *
+FROM users
+WHERE lower(handle) = lower($1);
+
+-- name: GetUserByID :one
+SELECT *
+FROM users
+WHERE id = $1;
+
+-- name: GetUserByIDForUpdate :one
+SELECT *
+FROM users
+WHERE id = $1
+FOR UPDATE;
+
 -- name: UpdateUser :one
 UPDATE users
 SET email = $2, hashed_password = $3
 WHERE id = $1
-RETURNING id, created_at, updated_at, email, is_chirpy_red;
+RETURNING id, created_at, updated_at, email, is_chirpy_red, handle;
 
 -- name: UpgradeUserToChirpyRed :exec
 UPDATE users
 SET is_chirpy_red = true, updated_at = NOW()
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: GetUserStatus :one
+SELECT status
+FROM users
+WHERE id = $1;
+
+-- name: IsHandleTaken :one
+SELECT EXISTS (
+    SELECT 1
+    FROM users
+    WHERE lower(handle) = lower(sqlc.arg('handle'))
+      AND id <> sqlc.arg('user_id')
+) OR EXISTS (
+    SELECT 1
+    FROM handle_redirects
+    WHERE handle = lower(sqlc.arg('handle'))
+      AND user_id <> sqlc.arg('user_id')
+      AND expires_at > NOW()
+);
+
+-- name: ListShadowbannedOrSuspendedUsers :many
+SELECT id
+FROM users
+WHERE id = ANY(sqlc.arg('ids')::uuid[])
+  AND status IN ('shadowbanned', 'suspended');
+
+-- name: UpdateUserStatus :exec
+UPDATE users
+SET status = $2,
+    status_changed_at = CASE WHEN status = $2 THEN status_changed_at ELSE NOW() END,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: UpdateUserHandle :one
+UPDATE users
+SET handle = $2, handle_changed_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserAutoDelete :one
+UPDATE users
+SET auto_delete_after_days = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
\ No newline at end of file
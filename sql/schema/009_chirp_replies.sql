@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN parent_chirp_id UUID REFERENCES chirps(id) ON DELETE SET NULL,
+ADD COLUMN tombstoned_at TIMESTAMP;
+
+CREATE INDEX chirps_parent_chirp_id_idx ON chirps (parent_chirp_id);
+
+
+-- +goose Down
+DROP INDEX IF EXISTS chirps_parent_chirp_id_idx;
+
+ALTER TABLE chirps
+DROP COLUMN tombstoned_at,
+DROP COLUMN parent_chirp_id;
\ No newline at end of file
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN quoted_chirp_id UUID REFERENCES chirps(id) ON DELETE SET NULL;
+
+CREATE INDEX chirps_quoted_chirp_id_created_at_id_idx ON chirps (quoted_chirp_id, created_at, id);
+
+CREATE TABLE rechirps (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, chirp_id)
+);
+
+CREATE INDEX rechirps_chirp_id_created_at_user_id_idx ON rechirps (chirp_id, created_at, user_id);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS rechirps;
+
+DROP INDEX IF EXISTS chirps_quoted_chirp_id_created_at_id_idx;
+
+ALTER TABLE chirps
+DROP COLUMN quoted_chirp_id;
\ No newline at end of file
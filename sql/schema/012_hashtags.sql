@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE hashtags (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    tag TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE chirp_hashtags (
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    hashtag_id UUID NOT NULL REFERENCES hashtags(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (chirp_id, hashtag_id)
+);
+
+CREATE INDEX chirp_hashtags_hashtag_id_idx ON chirp_hashtags (hashtag_id);
+CREATE INDEX chirp_hashtags_created_at_idx ON chirp_hashtags (created_at);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS chirp_hashtags;
+DROP TABLE IF EXISTS hashtags;
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE chirp_mentions (
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (chirp_id, user_id)
+);
+
+CREATE INDEX chirp_mentions_user_id_idx ON chirp_mentions (user_id);
+CREATE INDEX users_lower_email_idx ON users (lower(email));
+
+
+-- +goose Down
+DROP INDEX IF EXISTS users_lower_email_idx;
+DROP TABLE IF EXISTS chirp_mentions;
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE media_attachments (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chirp_id UUID REFERENCES chirps(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    content_type TEXT NOT NULL,
+    storage_key TEXT NOT NULL,
+    thumbnail_key TEXT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    alt_text TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX media_attachments_chirp_id_idx ON media_attachments (chirp_id, position);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS media_attachments;
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN publish_at TIMESTAMP;
+
+CREATE INDEX chirps_publish_at_idx ON chirps (publish_at) WHERE publish_at IS NOT NULL;
+
+
+-- +goose Down
+DROP INDEX IF EXISTS chirps_publish_at_idx;
+
+ALTER TABLE chirps
+DROP COLUMN publish_at;
\ No newline at end of file
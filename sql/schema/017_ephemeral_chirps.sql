@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN expires_at TIMESTAMP;
+
+CREATE INDEX chirps_expires_at_idx ON chirps (expires_at) WHERE expires_at IS NOT NULL;
+
+ALTER TABLE users
+ADD COLUMN auto_delete_after_days INTEGER;
+
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN auto_delete_after_days;
+
+DROP INDEX IF EXISTS chirps_expires_at_idx;
+
+ALTER TABLE chirps
+DROP COLUMN expires_at;
\ No newline at end of file
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE banned_words (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    word TEXT NOT NULL UNIQUE,
+    severity TEXT NOT NULL CHECK (severity IN ('mask', 'reject'))
+);
+
+INSERT INTO banned_words (id, created_at, updated_at, word, severity)
+VALUES
+    (gen_random_uuid(), NOW(), NOW(), 'kerfuffle', 'mask'),
+    (gen_random_uuid(), NOW(), NOW(), 'sharbert', 'mask'),
+    (gen_random_uuid(), NOW(), NOW(), 'fornax', 'mask');
+
+
+-- +goose Down
+DROP TABLE IF EXISTS banned_words;
\ No newline at end of file
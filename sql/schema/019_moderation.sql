@@ -0,0 +1,42 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN hidden_at TIMESTAMP;
+
+ALTER TABLE users
+ADD COLUMN suspended_at TIMESTAMP;
+
+CREATE TABLE moderation_actions (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    chirp_id UUID REFERENCES chirps(id) ON DELETE SET NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action TEXT NOT NULL CHECK (action IN ('auto_hide', 'dismiss', 'hide', 'suspend')),
+    report_count INTEGER NOT NULL,
+    note TEXT NOT NULL
+);
+
+CREATE INDEX moderation_actions_keyset_idx ON moderation_actions (created_at DESC, id DESC);
+
+CREATE TABLE reports (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL CHECK (reason IN ('spam', 'harassment', 'hate', 'violence', 'sexual', 'misinformation', 'other')),
+    details TEXT NOT NULL,
+    resolved_at TIMESTAMP,
+    resolved_by UUID REFERENCES moderation_actions(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX reports_open_reporter_idx ON reports (chirp_id, reporter_id) WHERE resolved_at IS NULL;
+
+
+-- +goose Down
+DROP TABLE IF EXISTS reports;
+DROP TABLE IF EXISTS moderation_actions;
+
+ALTER TABLE users
+DROP COLUMN suspended_at;
+
+ALTER TABLE chirps
+DROP COLUMN hidden_at;
\ No newline at end of file
@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE user_blocks (
+    blocker_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX user_blocks_blocked_id_idx ON user_blocks (blocked_id);
+
+CREATE TABLE user_mutes (
+    muter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    muted_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (muter_id, muted_id),
+    CHECK (muter_id <> muted_id)
+);
+
+-- Authors whose chirps are left out of a viewer's listings: everyone the
+-- viewer blocked or muted, and everyone who blocked the viewer.
+CREATE VIEW viewer_hidden_authors AS
+SELECT blocker_id AS viewer_id, blocked_id AS author_id FROM user_blocks
+UNION
+SELECT blocked_id AS viewer_id, blocker_id AS author_id FROM user_blocks
+UNION
+SELECT muter_id AS viewer_id, muted_id AS author_id FROM user_mutes;
+
+
+-- +goose Down
+DROP VIEW IF EXISTS viewer_hidden_authors;
+DROP TABLE IF EXISTS user_mutes;
+DROP TABLE IF EXISTS user_blocks;
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE mute_rules (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('keyword', 'hashtag')),
+    value TEXT NOT NULL,
+    scope TEXT NOT NULL CHECK (scope IN ('timeline', 'everywhere')),
+    expires_at TIMESTAMP
+);
+
+CREATE INDEX mute_rules_user_id_idx ON mute_rules (user_id);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS mute_rules;
\ No newline at end of file
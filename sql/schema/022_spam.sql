@@ -0,0 +1,28 @@
+-- +goose Up
+-- Chirps held by the spam checks are queued for moderation through a report
+-- without a reporter.
+ALTER TABLE reports
+ALTER COLUMN reporter_id DROP NOT NULL;
+
+ALTER TABLE moderation_actions
+DROP CONSTRAINT moderation_actions_action_check;
+
+ALTER TABLE moderation_actions
+ADD CONSTRAINT moderation_actions_action_check CHECK (action IN ('auto_hide', 'dismiss', 'hide', 'spam_hold', 'suspend'));
+
+
+-- +goose Down
+DELETE FROM moderation_actions
+WHERE action = 'spam_hold';
+
+ALTER TABLE moderation_actions
+DROP CONSTRAINT moderation_actions_action_check;
+
+ALTER TABLE moderation_actions
+ADD CONSTRAINT moderation_actions_action_check CHECK (action IN ('auto_hide', 'dismiss', 'hide', 'suspend'));
+
+DELETE FROM reports
+WHERE reporter_id IS NULL;
+
+ALTER TABLE reports
+ALTER COLUMN reporter_id SET NOT NULL;
\ No newline at end of file
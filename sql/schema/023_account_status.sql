@@ -0,0 +1,59 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended', 'shadowbanned')),
+ADD COLUMN status_changed_at TIMESTAMP;
+
+UPDATE users
+SET status = 'suspended', status_changed_at = suspended_at
+WHERE suspended_at IS NOT NULL;
+
+ALTER TABLE users
+DROP COLUMN suspended_at;
+
+CREATE INDEX users_shadowbanned_idx ON users (id) WHERE status = 'shadowbanned';
+
+ALTER TABLE moderation_actions
+DROP CONSTRAINT moderation_actions_action_check;
+
+ALTER TABLE moderation_actions
+ADD CONSTRAINT moderation_actions_action_check CHECK (action IN ('auto_hide', 'dismiss', 'hide', 'reinstate', 'shadowban', 'spam_hold', 'suspend'));
+
+CREATE TABLE appeals (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    suspended_at TIMESTAMP NOT NULL,
+    message TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('pending', 'approved', 'denied')),
+    decided_at TIMESTAMP,
+    decision_note TEXT NOT NULL DEFAULT '',
+    UNIQUE (user_id, suspended_at)
+);
+
+CREATE INDEX appeals_status_created_at_idx ON appeals (status, created_at, id);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS appeals;
+
+DELETE FROM moderation_actions
+WHERE action IN ('reinstate', 'shadowban');
+
+ALTER TABLE moderation_actions
+DROP CONSTRAINT moderation_actions_action_check;
+
+ALTER TABLE moderation_actions
+ADD CONSTRAINT moderation_actions_action_check CHECK (action IN ('auto_hide', 'dismiss', 'hide', 'spam_hold', 'suspend'));
+
+DROP INDEX IF EXISTS users_shadowbanned_idx;
+
+ALTER TABLE users
+ADD COLUMN suspended_at TIMESTAMP;
+
+UPDATE users
+SET suspended_at = COALESCE(status_changed_at, NOW())
+WHERE status = 'suspended';
+
+ALTER TABLE users
+DROP COLUMN status_changed_at,
+DROP COLUMN status;
\ No newline at end of file
@@ -0,0 +1,37 @@
+-- +goose Up
+-- Entries are never changed or removed, not even by a database reset.
+CREATE TABLE audit_log (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    actor_type TEXT NOT NULL CHECK (actor_type IN ('admin', 'user', 'webhook')),
+    actor_id UUID,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id UUID,
+    request_id TEXT NOT NULL,
+    ip TEXT NOT NULL,
+    diff JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX audit_log_created_at_id_idx ON audit_log (created_at, id);
+CREATE INDEX audit_log_target_idx ON audit_log (target_type, target_id);
+
+-- +goose StatementBegin
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER audit_log_no_update_or_delete
+BEFORE UPDATE OR DELETE ON audit_log
+FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+BEFORE TRUNCATE ON audit_log
+FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
\ No newline at end of file
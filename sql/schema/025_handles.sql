@@ -0,0 +1,33 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN handle TEXT,
+ADD COLUMN handle_changed_at TIMESTAMP;
+
+-- Existing users get a placeholder handle, which they can change right away.
+UPDATE users
+SET handle = 'user_' || substr(replace(id::text, '-', ''), 1, 12);
+
+ALTER TABLE users
+ALTER COLUMN handle SET NOT NULL;
+
+CREATE UNIQUE INDEX users_handle_idx ON users (lower(handle));
+
+-- Old handles keep pointing at their user until expires_at, and nobody else
+-- can take them until then. handle is stored lowercased.
+CREATE TABLE handle_redirects (
+    handle TEXT PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX handle_redirects_user_id_idx ON handle_redirects (user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS handle_redirects;
+
+DROP INDEX IF EXISTS users_handle_idx;
+
+ALTER TABLE users
+DROP COLUMN handle_changed_at,
+DROP COLUMN handle;
\ No newline at end of file
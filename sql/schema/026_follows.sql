@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE follows (
+    follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followed_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (follower_id, followed_id),
+    CHECK (follower_id <> followed_id)
+);
+
+-- Keyset indexes for listing who a user follows and who follows them. The
+-- timeline reads the primary key and chirps_user_id_created_at_id_idx.
+CREATE INDEX follows_following_keyset_idx ON follows (follower_id, created_at, followed_id);
+CREATE INDEX follows_followers_keyset_idx ON follows (followed_id, created_at, follower_id);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS follows;
\ No newline at end of file
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Tombstoned chirps keep their expiry but are never reaped again, so they are
+-- left out of the index the reaper scans.
+DROP INDEX IF EXISTS chirps_expires_at_idx;
+CREATE INDEX chirps_expires_at_idx ON chirps (expires_at) WHERE expires_at IS NOT NULL AND tombstoned_at IS NULL;
+
+CREATE INDEX users_auto_delete_idx ON users (id) WHERE auto_delete_after_days IS NOT NULL;
+
+
+-- +goose Down
+DROP INDEX IF EXISTS users_auto_delete_idx;
+
+DROP INDEX IF EXISTS chirps_expires_at_idx;
+CREATE INDEX chirps_expires_at_idx ON chirps (expires_at) WHERE expires_at IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Mentions resolve by handle, which users_handle_idx already covers.
+DROP INDEX IF EXISTS users_lower_email_idx;
+
+
+-- +goose Down
+CREATE INDEX users_lower_email_idx ON users (lower(email));
\ No newline at end of file
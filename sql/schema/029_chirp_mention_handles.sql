@@ -0,0 +1,18 @@
+-- +goose Up
+-- The handle a chirp used for the mention, lowercased, so the mention keeps
+-- pointing at the user after they change their handle.
+ALTER TABLE chirp_mentions
+ADD COLUMN handle TEXT;
+
+UPDATE chirp_mentions
+SET handle = lower(users.handle)
+FROM users
+WHERE users.id = chirp_mentions.user_id;
+
+ALTER TABLE chirp_mentions
+ALTER COLUMN handle SET NOT NULL;
+
+
+-- +goose Down
+ALTER TABLE chirp_mentions
+DROP COLUMN handle;
\ No newline at end of file
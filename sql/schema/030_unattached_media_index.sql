@@ -0,0 +1,7 @@
+-- +goose Up
+-- Uploads that never got attached to a chirp are reaped oldest first.
+CREATE INDEX media_attachments_unattached_idx ON media_attachments (created_at) WHERE chirp_id IS NULL;
+
+
+-- +goose Down
+DROP INDEX IF EXISTS media_attachments_unattached_idx;
\ No newline at end of file
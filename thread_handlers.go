@@ -0,0 +1,126 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"net/http"
+)
+
+func (cfg *apiConfig) handleGetChirpThread(w http.ResponseWriter, r *http.Request) {
+	chirpIDString := r.PathValue("id")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid Chirp ID")
+		return
+	}
+
+	dbChirp, err := cfg.dbQueries.GetChirpByID(r.Context(), chirpID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+			return
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	viewerID := cfg.viewerID(r)
+	if hiddenScheduledChirp(dbChirp, viewerID) {
+		respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return
+	}
+
+	hidden, err := cfg.authorHiddenFrom(r.Context(), viewerID, dbChirp.UserID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if hidden {
+		respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return
+	}
+
+	hiddenAuthors, err := cfg.hiddenAuthors(r.Context(), viewerID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	dbAncestors, err := cfg.dbQueries.GetChirpAncestors(r.Context(), chirpID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	dbReplies, err := cfg.dbQueries.GetChirpReplies(r.Context(), uuid.NullUUID{UUID: chirpID, Valid: true})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	allChirps := append(append(dbAncestors, dbChirp), dbReplies...)
+	err = cfg.addShadowbannedOrSuspendedAuthors(r.Context(), viewerID, allChirps, hiddenAuthors)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	chirps, err := cfg.chirpsForResponse(r.Context(), viewerID, allChirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	chirpsByID := make(map[uuid.UUID]Chirp, len(chirps))
+	for i, chirp := range chirps {
+		// Trashed, expired and hidden chirps stay in the thread as
+		// placeholders, like tombstones, so replies below them remain
+		// reachable. So do chirps by authors the viewer blocked, muted or
+		// cannot see because they are shadowbanned or suspended.
+		if chirpRemoved(allChirps[i]) || (hiddenAuthors[chirp.UserID] && chirp.ID != dbChirp.ID) {
+			chirp.Body = ""
+			chirp.Media = []MediaAttachment{}
+			chirp.Entities = chirpEntities("", nil)
+			chirp.Deleted = true
+			chirp.DeletedAt = nil
+			chirp.ExpiresAt = nil
+			chirps[i] = chirp
+		}
+		chirpsByID[chirp.ID] = chirp
+	}
+
+	respondWithJSON(w, http.StatusOK, ChirpThread{
+		Ancestors: chirps[:len(dbAncestors)],
+		Chirp: ChirpThreadNode{
+			Chirp:   chirpsByID[dbChirp.ID],
+			Replies: buildReplyTree(dbChirp.ID, dbReplies, chirpsByID),
+		},
+	})
+}
+
+// buildReplyTree nests replies under their parents. Replies are expected in
+// creation order, which keeps siblings ordered oldest first.
+func buildReplyTree(rootID uuid.UUID, dbReplies []database.Chirp, chirpsByID map[uuid.UUID]Chirp) []ChirpThreadNode {
+	children := make(map[uuid.UUID][]uuid.UUID)
+	for _, dbReply := range dbReplies {
+		children[dbReply.ParentChirpID.UUID] = append(children[dbReply.ParentChirpID.UUID], dbReply.ID)
+	}
+
+	var build func(parentID uuid.UUID) []ChirpThreadNode
+	build = func(parentID uuid.UUID) []ChirpThreadNode {
+		nodes := []ChirpThreadNode{}
+		for _, replyID := range children[parentID] {
+			nodes = append(nodes, ChirpThreadNode{
+				Chirp:   chirpsByID[replyID],
+				Replies: build(replyID),
+			})
+		}
+		return nodes
+	}
+
+	return build(rootID)
+}
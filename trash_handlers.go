@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pedroomedicina/chirpy/internal/auth"
+	"github.com/pedroomedicina/chirpy/internal/database"
+	"github.com/pedroomedicina/chirpy/internal/pagination"
+	"net/http"
+	"time"
+)
+
+const (
+	purgeInterval  = 10 * time.Minute
+	purgeBatchSize = 100
+)
+
+func (cfg *apiConfig) handleGetTrash(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := database.ListTrashedChirpsParams{
+		UserID: userID,
+		Limit:  limit + 1,
+	}
+
+	cursorQueryParam := r.URL.Query().Get("cursor")
+	if cursorQueryParam != "" {
+		cursor, err := pagination.DecodeCursor(cursorQueryParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.CursorDeletedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	dbChirps, err := cfg.dbQueries.ListTrashedChirps(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	// The trash is ordered by when chirps were deleted, so the cursor carries
+	// deleted_at.
+	var nextCursor string
+	if len(dbChirps) > int(limit) {
+		dbChirps = dbChirps[:limit]
+		last := dbChirps[len(dbChirps)-1]
+		nextCursor = pagination.EncodeCursor(pagination.Cursor{
+			CreatedAt: last.DeletedAt.Time,
+			ID:        last.ID,
+		})
+	}
+
+	chirps, err := cfg.chirpsForResponse(r.Context(), uuid.NullUUID{UUID: userID, Valid: true}, dbChirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, ChirpsPage{
+		Chirps:     chirps,
+		NextCursor: nextCursor,
+	})
+}
+
+func (cfg *apiConfig) handleRestoreChirp(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Missing or invalid authorization token")
+		return
+	}
+
+	userID, err := cfg.authenticate(r.Context(), accessToken)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	chirpIDString := r.PathValue("id")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid Chirp ID")
+		return
+	}
+
+	dbChirp, err := cfg.dbQueries.GetChirpByID(r.Context(), chirpID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+			return
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if dbChirp.UserID != userID || !dbChirp.DeletedAt.Valid {
+		respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return
+	}
+
+	// RestoreChirp only matches chirps still in the trash, so a chirp purged
+	// in the meantime comes back as not found.
+	restoredChirp, err := cfg.dbQueries.RestoreChirp(r.Context(), dbChirp.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+			return
+		}
+
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	chirps, err := cfg.chirpsForResponse(r.Context(), uuid.NullUUID{UUID: userID, Valid: true}, []database.Chirp{restoredChirp})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, chirps[0])
+}
+
+// purgeTrashedChirps permanently removes a batch of chirps that have been in
+// the trash for longer than the retention period. Rows are claimed with FOR
+// UPDATE SKIP LOCKED so several server instances can purge side by side, and a
+// restore racing with the purge waits for it to finish.
+func (cfg *apiConfig) purgeTrashedChirps(ctx context.Context) (int, error) {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	chirpIDs, err := qtx.ListPurgeableChirps(ctx, database.ListPurgeableChirpsParams{
+		RetentionSeconds: int32(cfg.trashRetention.Seconds()),
+		Limit:            purgeBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var attachments []database.MediaAttachment
+	for _, chirpID := range chirpIDs {
+		purgedAttachments, err := purgeChirp(ctx, qtx, chirpID)
+		if err != nil {
+			return 0, err
+		}
+		attachments = append(attachments, purgedAttachments...)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	cfg.removeMediaFiles(ctx, attachments)
+	return len(chirpIDs), nil
+}
@@ -1,24 +1,271 @@
 package main
 
 import (
+	"encoding/json"
 	"github.com/google/uuid"
 	"time"
 )
 
 type User struct {
-	ID           uuid.UUID `json:"id"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	Email        string    `json:"email"`
-	Token        string    `json:"token"`
-	RefreshToken string    `json:"refresh_token"`
-	IsChirpyRed  bool      `json:"is_chirpy_red"`
+	ID                  uuid.UUID `json:"id"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+	Email               string    `json:"email"`
+	Handle              string    `json:"handle"`
+	Token               string    `json:"token"`
+	RefreshToken        string    `json:"refresh_token"`
+	IsChirpyRed         bool      `json:"is_chirpy_red"`
+	AutoDeleteAfterDays *int32    `json:"auto_delete_after_days,omitempty"`
+}
+
+// Profile is the public view of a user. It never includes the email address.
+type Profile struct {
+	ID          uuid.UUID `json:"id"`
+	Handle      string    `json:"handle"`
+	CreatedAt   time.Time `json:"created_at"`
+	IsChirpyRed bool      `json:"is_chirpy_red"`
 }
 
 type Chirp struct {
+	ID                  uuid.UUID         `json:"id"`
+	CreatedAt           time.Time         `json:"created_at"`
+	UpdatedAt           time.Time         `json:"updated_at"`
+	Body                string            `json:"body"`
+	UserID              uuid.UUID         `json:"user_id"`
+	InReplyTo           *uuid.UUID        `json:"in_reply_to,omitempty"`
+	QuotedChirpID       *uuid.UUID        `json:"quoted_chirp_id,omitempty"`
+	PublishAt           *time.Time        `json:"publish_at,omitempty"`
+	ExpiresAt           *time.Time        `json:"expires_at,omitempty"`
+	Deleted             bool              `json:"deleted,omitempty"`
+	DeletedAt           *time.Time        `json:"deleted_at,omitempty"`
+	RechirpCount        int64             `json:"rechirp_count"`
+	QuoteCount          int64             `json:"quote_count"`
+	LikeCount           int64             `json:"like_count"`
+	LikedByMe           *bool             `json:"liked_by_me,omitempty"`
+	Media               []MediaAttachment `json:"media"`
+	Entities            *ChirpEntities    `json:"entities,omitempty"`
+	RemainingCharacters *int              `json:"remaining_characters,omitempty"`
+	HeldForModeration   bool              `json:"held_for_moderation,omitempty"`
+}
+
+type ChirpValidation struct {
+	Valid               bool   `json:"valid"`
+	Length              int    `json:"length"`
+	RemainingCharacters int    `json:"remaining_characters"`
+	Error               string `json:"error,omitempty"`
+}
+
+// ChirpEntities locates the links, hashtags and mentions in a chirp body.
+// Start and End are code point offsets into the body, with End exclusive.
+type ChirpEntities struct {
+	URLs     []URLEntity     `json:"urls"`
+	Hashtags []HashtagEntity `json:"hashtags"`
+	Mentions []MentionEntity `json:"mentions"`
+}
+
+type URLEntity struct {
+	URL   string `json:"url"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+type HashtagEntity struct {
+	Tag   string `json:"tag"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+type MentionEntity struct {
+	Handle string     `json:"handle"`
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+	Start  int        `json:"start"`
+	End    int        `json:"end"`
+}
+
+type MediaAttachment struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url,omitempty"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+	ContentType  string    `json:"content_type,omitempty"`
+	Width        int32     `json:"width,omitempty"`
+	Height       int32     `json:"height,omitempty"`
+	AltText      string    `json:"alt_text"`
+}
+
+type ChirpRevision struct {
 	ID        uuid.UUID `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ChirpID   uuid.UUID `json:"chirp_id"`
 	Body      string    `json:"body"`
+}
+
+type ChirpsPage struct {
+	Chirps     []Chirp `json:"chirps"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+type ChirpThreadNode struct {
+	Chirp
+	Replies []ChirpThreadNode `json:"replies"`
+}
+
+type ChirpThread struct {
+	Ancestors []Chirp         `json:"ancestors"`
+	Chirp     ChirpThreadNode `json:"chirp"`
+}
+
+type TrendingHashtag struct {
+	Tag           string  `json:"tag"`
+	RecentCount   int64   `json:"recent_count"`
+	PreviousCount int64   `json:"previous_count"`
+	Growth        float64 `json:"growth"`
+}
+
+type Rechirp struct {
 	UserID    uuid.UUID `json:"user_id"`
+	ChirpID   uuid.UUID `json:"chirp_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RechirpsPage struct {
+	Rechirps   []Rechirp `json:"rechirps"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// RelatedUser is an entry in the caller's list of blocked or muted users, or in
+// a user's followers or following. Handle is only filled in for follows.
+type RelatedUser struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Handle    string    `json:"handle,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RelatedUsersPage struct {
+	Users      []RelatedUser `json:"users"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+type MuteRule struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	Kind      string     `json:"kind"`
+	Value     string     `json:"value"`
+	Scope     string     `json:"scope"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type SpamConfig struct {
+	DuplicateWindowSeconds int     `json:"duplicate_window_seconds"`
+	DuplicateSimilarity    float64 `json:"duplicate_similarity"`
+	DuplicateWeight        float64 `json:"duplicate_weight"`
+	BurstWindowSeconds     int     `json:"burst_window_seconds"`
+	BurstLimit             int     `json:"burst_limit"`
+	BurstWeight            float64 `json:"burst_weight"`
+	NewAccountAgeSeconds   int     `json:"new_account_age_seconds"`
+	NewAccountMaxLinks     int     `json:"new_account_max_links"`
+	LinkWeight             float64 `json:"link_weight"`
+	MaxMentions            int     `json:"max_mentions"`
+	MentionWeight          float64 `json:"mention_weight"`
+	FlagWeight             float64 `json:"flag_weight"`
+	HoldScore              float64 `json:"hold_score"`
+	RejectScore            float64 `json:"reject_score"`
+}
+
+type Entitlements struct {
+	Plan                 string `json:"plan"`
+	MaxChirpLength       int    `json:"max_chirp_length"`
+	MaxMediaPerChirp     int    `json:"max_media_per_chirp"`
+	EditWindowSeconds    int    `json:"edit_window_seconds"`
+	DailyChirpQuota      int    `json:"daily_chirp_quota"`
+	RemainingDailyChirps int    `json:"remaining_daily_chirps"`
+	WritesPerMinute      int    `json:"writes_per_minute"`
+}
+
+type BannedWord struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Word      string    `json:"word"`
+	Severity  string    `json:"severity"`
+}
+
+type ContentFilterPreview struct {
+	Text     string        `json:"text"`
+	Rejected bool          `json:"rejected"`
+	Reason   string        `json:"reason,omitempty"`
+	Flags    []ContentFlag `json:"flags"`
+}
+
+type ContentFlag struct {
+	Filter string `json:"filter"`
+	Reason string `json:"reason"`
+}
+
+type ReportedChirp struct {
+	Chirp           Chirp            `json:"chirp"`
+	ReportCount     int64            `json:"report_count"`
+	Reasons         map[string]int64 `json:"reasons"`
+	FirstReportedAt time.Time        `json:"first_reported_at"`
+	LastReportedAt  time.Time        `json:"last_reported_at"`
+	Hidden          bool             `json:"hidden"`
+}
+
+type ReportedChirpsPage struct {
+	ReportedChirps []ReportedChirp `json:"reported_chirps"`
+	NextCursor     string          `json:"next_cursor,omitempty"`
+}
+
+type ModerationAction struct {
+	ID          uuid.UUID  `json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ChirpID     *uuid.UUID `json:"chirp_id,omitempty"`
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
+	Action      string     `json:"action"`
+	ReportCount int32      `json:"report_count"`
+	Note        string     `json:"note,omitempty"`
+}
+
+type ModerationActionsPage struct {
+	Actions    []ModerationAction `json:"actions"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+type AuditLogEntry struct {
+	ID         uuid.UUID       `json:"id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	ActorType  string          `json:"actor_type"`
+	ActorID    *uuid.UUID      `json:"actor_id,omitempty"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   *uuid.UUID      `json:"target_id,omitempty"`
+	RequestID  string          `json:"request_id"`
+	IP         string          `json:"ip"`
+	Diff       json.RawMessage `json:"diff"`
+}
+
+type AuditLogPage struct {
+	Entries    []AuditLogEntry `json:"entries"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+type AccountStatus struct {
+	UserID          uuid.UUID  `json:"user_id"`
+	Status          string     `json:"status"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+}
+
+type Appeal struct {
+	ID           uuid.UUID  `json:"id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UserID       uuid.UUID  `json:"user_id"`
+	SuspendedAt  time.Time  `json:"suspended_at"`
+	Message      string     `json:"message"`
+	Status       string     `json:"status"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+	DecisionNote string     `json:"decision_note,omitempty"`
+}
+
+type AppealsPage struct {
+	Appeals    []Appeal `json:"appeals"`
+	NextCursor string   `json:"next_cursor,omitempty"`
 }